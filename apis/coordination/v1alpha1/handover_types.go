@@ -93,6 +93,9 @@ type HandoverRef struct {
 
 const (
 	HandoverCompleted = "Completed"
+	// ProbesInvalid condition is True when the probes are misconfigured.
+	// The handover is stopped until the probes are fixed.
+	HandoverProbesInvalid = "ProbesInvalid"
 )
 
 type HandoverPhase string
@@ -8,7 +8,7 @@ import (
 type ClusterObjectSetSpec struct {
 	// Specifies the lifecycle state of the ObjectSet.
 	// +kubebuilder:default="Active"
-	// +kubebuilder:validation:Enum=Active;Paused;Archived
+	// +kubebuilder:validation:Enum=Active;Paused;Archived;Orphaned
 	LifecycleState ObjectSetLifecycleState `json:"lifecycleState,omitempty"`
 	// Pause reconcilation of specific objects, while still reporting status.
 	PausedFor []ObjectSetPausedObject `json:"pausedFor,omitempty"`
@@ -26,6 +26,13 @@ type ClusterObjectSetStatus struct {
 	Phase ObjectSetStatusPhase `json:"phase,omitempty"`
 	// List of objects, the controller has paused reconcilation on.
 	PausedFor []ObjectSetPausedObject `json:"pausedFor,omitempty"`
+	// Status of each reconcile phase.
+	Phases []ObjectPhaseStatus `json:"phases,omitempty"`
+	// Summary of drift detected on managed objects
+	// of phases reconciled in-process.
+	Drift *ObjectSetDriftStatus `json:"drift,omitempty"`
+	// Results of hooks run for this revision.
+	Hooks []HookStatus `json:"hooks,omitempty"`
 }
 
 // ClusterObjectSet reconcile a collection of objects across ordered phases and aggregate their status.
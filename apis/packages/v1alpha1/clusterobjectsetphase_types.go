@@ -9,11 +9,26 @@ type ClusterObjectSetPhaseSpec struct {
 	Paused bool `json:"paused,omitempty"`
 	// Pause reconcilation of specific objects.
 	PausedFor []ObjectSetPausedObject `json:"pausedFor,omitempty"`
+	// Orphaned releases all objects of the phase instead of deleting them,
+	// when the ClusterObjectSetPhase is deleted.
+	Orphaned bool `json:"orphaned,omitempty"`
 
 	// Readiness Probes check objects that are part of the package.
 	// All probes need to succeed for a package to be considered Available.
 	// Failing probes will prevent the reconcilation of objects in later phases.
 	ReadinessProbes []ObjectSetProbe `json:"readinessProbes"`
+	// Strategy used to apply objects.
+	// Defaults to the global default of the phase controller.
+	// +kubebuilder:validation:Enum=MergePatch;ServerSideApply
+	ApplyMode ObjectSetApplyMode `json:"applyMode,omitempty"`
+	// Specifies how changes to managed objects by other actors are handled.
+	// +kubebuilder:default="Correct"
+	// +kubebuilder:validation:Enum=Correct;Report;Ignore
+	DriftPolicy ObjectSetDriftPolicy `json:"driftPolicy,omitempty"`
+	// Specifies when pre-existing objects not managed by package-operator are adopted.
+	// +kubebuilder:default="IfNoController"
+	// +kubebuilder:validation:Enum=Never;IfNoController;Always
+	AdoptionPolicy ObjectSetAdoptionPolicy `json:"adoptionPolicy,omitempty"`
 
 	// Immutable fields below
 	ObjectPhase `json:",inline"`
@@ -25,6 +40,10 @@ type ClusterObjectSetPhaseStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// List of objects, the controller has paused reconcilation on.
 	PausedFor []ObjectSetPausedObject `json:"pausedFor,omitempty"`
+	// Status of the reconciled phase.
+	Phases []ObjectPhaseStatus `json:"phases,omitempty"`
+	// Summary of drift detected on managed objects.
+	Drift *ObjectSetDriftStatus `json:"drift,omitempty"`
 }
 
 // ClusterObjectSetPhase is the Schema for the ClusterObjectSetPhases API
@@ -13,6 +13,10 @@ type ClusterObjectDeploymentSpec struct {
 	Selector metav1.LabelSelector `json:"selector"`
 	// Template to create new ObjectSets from.
 	Template ObjectSetTemplate `json:"template"`
+	// Restores the template of a previous revision, when set.
+	// Cleared again, as soon as the template has been restored.
+	// +optional
+	RollbackTo *ObjectDeploymentRollback `json:"rollbackTo,omitempty"`
 }
 
 // ClusterObjectDeploymentStatus defines the observed state of a ClusterObjectDeployment
@@ -27,6 +31,8 @@ type ClusterObjectDeploymentStatus struct {
 	CollisionCount *int32 `json:"collisionCount,omitempty"`
 	// Computed TemplateHash.
 	TemplateHash string `json:"templateHash,omitempty"`
+	// Revision of the newest Available ObjectSet, serving the live objects.
+	Revision int64 `json:"revision,omitempty"`
 }
 
 // ClusterObjectDeployment is the Schema for the ClusterObjectDeployments API
@@ -34,6 +40,7 @@ type ClusterObjectDeploymentStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".status.revision"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type ClusterObjectDeployment struct {
 	metav1.TypeMeta   `json:",inline"`
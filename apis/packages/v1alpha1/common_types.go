@@ -1,6 +1,9 @@
 package v1alpha1
 
-import "k8s.io/apimachinery/pkg/runtime"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 // ObjectSet specification.
 type ObjectSetTemplateSpec struct {
@@ -12,16 +15,183 @@ type ObjectSetTemplateSpec struct {
 	// All probes need to succeed for a package to be considered Available.
 	// Failing probes will prevent the reconcilation of objects in later phases.
 	ReadinessProbes []ObjectSetProbe `json:"readinessProbes"`
+	// Strategy used to apply objects.
+	// Defaults to the global default of the package-operator.
+	// +kubebuilder:validation:Enum=MergePatch;ServerSideApply
+	ApplyMode ObjectSetApplyMode `json:"applyMode,omitempty"`
+	// Specifies how changes to managed objects by other actors are handled.
+	// +kubebuilder:default="Correct"
+	// +kubebuilder:validation:Enum=Correct;Report;Ignore
+	DriftPolicy ObjectSetDriftPolicy `json:"driftPolicy,omitempty"`
+	// Specifies when pre-existing objects not managed by package-operator are adopted.
+	// Can be overridden per object via the
+	// "packages.thetechnick.ninja/adoption-policy" annotation.
+	// +kubebuilder:default="IfNoController"
+	// +kubebuilder:validation:Enum=Never;IfNoController;Always
+	AdoptionPolicy ObjectSetAdoptionPolicy `json:"adoptionPolicy,omitempty"`
 }
 
-// Specifies that the reconcilation of a specific object should be paused.
+// Strategy used to apply objects.
+type ObjectSetApplyMode string
+
+const (
+	// "MergePatch" patches fields that differ from the desired state.
+	ObjectSetApplyModeMergePatch ObjectSetApplyMode = "MergePatch"
+	// "ServerSideApply" uses server-side apply with the package-operator field manager.
+	// Fields dropped from the desired state are removed
+	// and conflicts with other field managers are reported in status.
+	ObjectSetApplyModeServerSideApply ObjectSetApplyMode = "ServerSideApply"
+)
+
+// Specifies how changes to managed objects by other actors are handled.
+type ObjectSetDriftPolicy string
+
+const (
+	// "Correct" reverts drift back to the desired state and reports the correction.
+	ObjectSetDriftPolicyCorrect ObjectSetDriftPolicy = "Correct"
+	// "Report" leaves drifted objects alone, but reports them in status.
+	ObjectSetDriftPolicyReport ObjectSetDriftPolicy = "Report"
+	// "Ignore" leaves drifted objects alone without reporting them.
+	ObjectSetDriftPolicyIgnore ObjectSetDriftPolicy = "Ignore"
+)
+
+// Specifies when pre-existing objects not managed by package-operator are adopted.
+type ObjectSetAdoptionPolicy string
+
+const (
+	// "Never" adopts no pre-existing objects.
+	ObjectSetAdoptionPolicyNever ObjectSetAdoptionPolicy = "Never"
+	// "IfNoController" adopts pre-existing objects without controller, the default.
+	ObjectSetAdoptionPolicyIfNoController ObjectSetAdoptionPolicy = "IfNoController"
+	// "Always" adopts pre-existing objects, even when controlled by someone else.
+	ObjectSetAdoptionPolicyAlways ObjectSetAdoptionPolicy = "Always"
+)
+
+// Overrides the adoption policy for a single object.
+const AdoptionPolicyAnnotation = "packages.thetechnick.ninja/adoption-policy"
+
+// Specifies what happens to an object when its ObjectSet is archived or deleted.
+const DeletionPolicyAnnotation = "packages.thetechnick.ninja/deletion-policy"
+
+type DeletionPolicy string
+
+const (
+	// "Delete" deletes the object, this is the default.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// "Orphan" keeps the object and only removes the owner reference.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// Specifies how changes to the desired state of an object are reconciled.
+const ReconcileModeAnnotation = "packages.thetechnick.ninja/reconcile-mode"
+
+type ReconcileMode string
+
+const (
+	// "CreateOnly" creates the object, but never updates it.
+	ReconcileModeCreateOnly ReconcileMode = "CreateOnly"
+	// "Update" updates the object in place, this is the default.
+	ReconcileModeUpdate ReconcileMode = "Update"
+	// "Replace" deletes and recreates the object on change.
+	ReconcileModeReplace ReconcileMode = "Replace"
+)
+
+// Opts a single object into being recreated, when changes to immutable fields can't be applied.
+// The value is the deletion propagation policy: "Background", "Foreground" or "Orphan".
+const RecreateOnImmutableChangeAnnotation = "packages.thetechnick.ninja/recreate-on-immutable-change"
+
+// Revision of an ObjectSet created by an ObjectDeployment, starting at "1".
+const ObjectSetRevisionAnnotation = "packages.thetechnick.ninja/revision"
+
+// Marks a Job as hook, running at a specific point of the ObjectSet lifecycle,
+// instead of being reconciled with the other objects of its phase.
+// Hooks are only supported in phases reconciled in-process
+// and need to be inlined into the phase instead of being stored in slices.
+const HookAnnotation = "packages.thetechnick.ninja/hook"
+
+type HookType string
+
+const (
+	// "pre-install" runs before any phase is reconciled on the first revision.
+	HookPreInstall HookType = "pre-install"
+	// "post-install" runs after all phases became available on the first revision.
+	HookPostInstall HookType = "post-install"
+	// "pre-upgrade" runs before any phase is reconciled on later revisions.
+	HookPreUpgrade HookType = "pre-upgrade"
+	// "pre-delete" runs before objects are removed, when the ObjectSet is deleted.
+	HookPreDelete HookType = "pre-delete"
+)
+
+// Reports the result of a hook Job.
+type HookStatus struct {
+	// Name of the hook Job.
+	Name string `json:"name"`
+	// Type of the hook.
+	Type HookType `json:"type"`
+	// State of the hook.
+	// +kubebuilder:validation:Enum=Running;Succeeded;Failed;Conflict
+	State HookState `json:"state"`
+	// Reason for the hook failure or conflict.
+	Message string `json:"message,omitempty"`
+	// Time the hook Job completed or failed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type HookState string
+
+const (
+	// Hook Job has been created and is not finished yet.
+	HookStateRunning HookState = "Running"
+	// Hook Job completed successfully and will not run again for this revision.
+	HookStateSucceeded HookState = "Succeeded"
+	// Hook Job failed and is run again, when the Job is deleted.
+	HookStateFailed HookState = "Failed"
+	// Hook Job name is taken by an object not controlled by a revision of the same parent.
+	// The hook is run, when the object is deleted.
+	HookStateConflict HookState = "Conflict"
+)
+
+// Configures recreation of objects with changed immutable fields.
+type RecreateOnImmutableChange struct {
+	// Propagation policy used when deleting the object.
+	// "Foreground" waits for dependents to be deleted before recreating the object.
+	// +kubebuilder:default=Background
+	// +kubebuilder:validation:Enum=Background;Foreground;Orphan
+	PropagationPolicy metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+}
+
+// Summary of drift detected on managed objects.
+type ObjectSetDriftStatus struct {
+	// Number of drift corrections performed.
+	Corrections int64 `json:"corrections,omitempty"`
+	// Last time drift was detected.
+	LastDetectionTime *metav1.Time `json:"lastDetectionTime,omitempty"`
+	// Objects drifting from their desired state, if the drift policy is Report.
+	// Limited to the first 10 objects.
+	Objects []ObjectPhaseFailedObject `json:"objects,omitempty"`
+	// Hashes of all objects reported as drifting,
+	// so drift is only detected once, even beyond the first 10 objects.
+	ObjectHashes []string `json:"objectHashes,omitempty"`
+}
+
+// Specifies that the reconcilation of a specific object,
+// or of all objects matching a label selector, should be paused.
 type ObjectSetPausedObject struct {
 	// Object Kind.
 	Kind string `json:"kind"`
 	// Object Group.
 	Group string `json:"group"`
 	// Object Name.
-	Name string `json:"name"`
+	// Objects with any name are matched, if empty.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Object Namespace.
+	// Objects in any namespace are matched, if empty.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Only objects with labels matching this selector are matched, if set.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 }
 
 // ObjectSet reconcile phase.
@@ -31,20 +201,121 @@ type ObjectPhase struct {
 	// Class of the underlying phase controller.
 	Class string `json:"class,omitempty"`
 	// Objects belonging to this phase.
-	Objects []ObjectSetObject `json:"objects"`
+	Objects []ObjectSetObject `json:"objects,omitempty"`
+	// Names of ObjectSetSlices holding further objects of this phase,
+	// for ObjectSets too large to inline all objects.
+	// Slices are looked up in the namespace of the ObjectSet,
+	// ClusterObjectSets reference ClusterObjectSetSlices instead.
+	Slices []string `json:"slices,omitempty"`
+	// Names of phases that need to be ready, before this phase is reconciled.
+	// Phases without dependencies on each other are reconciled concurrently.
+	// If no phase declares dependencies, phases are reconciled in order.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Minimum number of seconds the readiness probes of this phase
+	// need to pass continuously, before the next phase is reconciled.
+	// Defaults to 0, continuing as soon as probes succeed.
+	// +kubebuilder:validation:Minimum=0
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+	// Maximum number of seconds for this phase to pass its readiness probes,
+	// before the ObjectSet reports the ProgressDeadlineExceeded reason.
+	// Defaults to 0, which disables the deadline.
+	// +kubebuilder:validation:Minimum=0
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds,omitempty"`
+	// Deletes and recreates objects of this phase,
+	// when a change to immutable fields can't be applied otherwise.
+	// Without this, such objects are reported via the ImmutableFieldConflict condition.
+	RecreateOnImmutableChange *RecreateOnImmutableChange `json:"recreateOnImmutableChange,omitempty"`
+	// Maximum number of objects of this phase reconciled concurrently.
+	// Defaults to the concurrency configured for the operator.
+	// Objects depending on each other, like CRDs and their instances,
+	// should be placed into separate phases when reconciled concurrently.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"`
+	// Propagation policy used to delete objects of this phase on teardown.
+	// "Foreground" keeps objects around until their dependents are deleted.
+	// Defaults to the propagation policy of the object kind, usually "Background".
+	// +kubebuilder:validation:Enum=Background;Foreground
+	TeardownPropagationPolicy metav1.DeletionPropagation `json:"teardownPropagationPolicy,omitempty"`
+	// Number of seconds objects of this phase may be terminating on teardown,
+	// before the ObjectSet reports them as stuck via the TeardownStuck reason.
+	// Defaults to 0, which disables the timeout.
+	// +kubebuilder:validation:Minimum=0
+	TeardownTimeoutSeconds int32 `json:"teardownTimeoutSeconds,omitempty"`
+}
+
+// Reports the state of a reconcile phase.
+type ObjectPhaseStatus struct {
+	// Name of the reconcile phase.
+	Name string `json:"name"`
+	// State of the phase.
+	// +kubebuilder:validation:Enum=Pending;Reconciling;Ready;Failed
+	State ObjectPhaseState `json:"state"`
+	// Number of objects in this phase.
+	Objects int32 `json:"objects"`
+	// Number of objects passing their readiness probes.
+	ReadyObjects int32 `json:"readyObjects"`
+	// Objects failing their readiness probes.
+	// Limited to the first 10 objects.
+	FailedObjects []ObjectPhaseFailedObject `json:"failedObjects,omitempty"`
+	// Objects with fields managed by other actors,
+	// preventing server-side apply of the desired state.
+	// Limited to the first 10 objects.
+	Conflicts []ObjectPhaseFailedObject `json:"conflicts,omitempty"`
+	// True if all readiness probes of the phase passed on the last check.
+	Ready bool `json:"ready"`
+	// Last time the phase transitioned between ready and not ready.
+	// Unset while the phase is Pending.
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Phases this phase is waiting for, while Pending.
+	BlockedBy []string `json:"blockedBy,omitempty"`
+}
+
+type ObjectPhaseState string
+
+const (
+	// Phase is waiting for the phases it depends on to become ready.
+	ObjectPhaseStatePending ObjectPhaseState = "Pending"
+	// Phase objects are reconciled, but not yet passing their probes
+	// or not yet passing them for minReadySeconds.
+	ObjectPhaseStateReconciling ObjectPhaseState = "Reconciling"
+	// All phase objects pass their probes.
+	ObjectPhaseStateReady ObjectPhaseState = "Ready"
+	// Phase did not become ready within progressDeadlineSeconds.
+	ObjectPhaseStateFailed ObjectPhaseState = "Failed"
+)
+
+// An object failing its readiness probes, failing to apply or drifting.
+type ObjectPhaseFailedObject struct {
+	// Object Group.
+	Group string `json:"group,omitempty"`
+	// Object Kind.
+	Kind string `json:"kind"`
+	// Object Namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Object Name.
+	Name string `json:"name"`
+	// Failure message.
+	Message string `json:"message"`
 }
 
 // An object that is part of an ObjectSet.
+// Either object or compressedObject must be set.
 type ObjectSetObject struct {
 	// +kubebuilder:validation:EmbeddedResource
 	// +kubebuilder:pruning:PreserveUnknownFields
-	Object runtime.RawExtension `json:"object"`
+	// +optional
+	Object runtime.RawExtension `json:"object,omitempty"`
+	// Gzip compressed JSON representation of the object,
+	// to reduce the storage size of large objects.
+	// +optional
+	CompressedObject []byte `json:"compressedObject,omitempty"`
 }
 
 // ObjectSetProbe define how ObjectSets check their children for their status.
 type ObjectSetProbe struct {
 	// Probe configuration parameters.
-	Probes []Probe `json:"probes"`
+	// Must be empty for the Default selector type, which supplies its own probes.
+	Probes []Probe `json:"probes,omitempty"`
 	// Selector specifies which objects this probe should target.
 	Selector ProbeSelector `json:"selector"`
 }
@@ -52,15 +323,24 @@ type ObjectSetProbe struct {
 type ProbeSelectorType string
 
 const (
-	ProbeSelectorKind ProbeSelectorType = "Kind"
+	ProbeSelectorKind  ProbeSelectorType = "Kind"
+	ProbeSelectorLabel ProbeSelectorType = "Label"
+	ProbeSelectorName  ProbeSelectorType = "Name"
+	// Default selects all objects of well-known kinds
+	// and probes them with built-in readiness checks.
+	ProbeSelectorDefault ProbeSelectorType = "Default"
 )
 
 type ProbeSelector struct {
 	// Type of the package probe.
-	// +kubebuilder:validation:Enum=Kind
+	// +kubebuilder:validation:Enum=Kind;Label;Name;Default
 	Type ProbeSelectorType `json:"type"`
 	// Kind specific configuration parameters. Only present if Type = Kind.
 	Kind *PackageProbeKindSpec `json:"kind,omitempty"`
+	// Label specific configuration parameters. Only present if Type = Label.
+	Label *PackageProbeLabelSpec `json:"label,omitempty"`
+	// Name specific configuration parameters. Only present if Type = Name.
+	Name *PackageProbeNameSpec `json:"name,omitempty"`
 }
 
 // Kind package probe parameters.
@@ -71,14 +351,58 @@ type PackageProbeKindSpec struct {
 	Kind string `json:"kind"`
 }
 
+// Label package probe parameters.
+type PackageProbeLabelSpec struct {
+	// Optional Object Group to further restrict the probe to.
+	// Requires Kind to be set.
+	Group string `json:"group,omitempty"`
+	// Optional Object Kind to further restrict the probe to.
+	Kind string `json:"kind,omitempty"`
+	// Label selector objects have to match to apply a probe to.
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// Name package probe parameters.
+type PackageProbeNameSpec struct {
+	// Optional Object Group to further restrict the probe to.
+	// Requires Kind to be set.
+	Group string `json:"group,omitempty"`
+	// Optional Object Kind to further restrict the probe to.
+	Kind string `json:"kind,omitempty"`
+	// Object Namespace to apply a probe to, supports glob patterns.
+	// Empty matches all namespaces.
+	Namespace string `json:"namespace,omitempty"`
+	// Object Name to apply a probe to, supports glob patterns.
+	Name string `json:"name"`
+}
+
 // Defines probe parameters to check parts of a package.
 type Probe struct {
 	// Type of the probe.
-	// +kubebuilder:validation:Enum=Condition;FieldsEqual
+	// +kubebuilder:validation:Enum=Condition;FieldsEqual;FieldValue;CEL;AnyOf;AllOf;Not
 	Type ProbeType `json:"type"`
 	// Condition specific configuration parameters. Only present if Type = Condition.
 	Condition   *ProbeConditionSpec   `json:"condition,omitempty"`
 	FieldsEqual *ProbeFieldsEqualSpec `json:"fieldsEqual,omitempty"`
+	// FieldValue specific configuration parameters. Only present if Type = FieldValue.
+	FieldValue *ProbeFieldValueSpec `json:"fieldValue,omitempty"`
+	// CEL specific configuration parameters. Only present if Type = CEL.
+	CEL *ProbeCELSpec `json:"cel,omitempty"`
+	// Probes of which at least one has to succeed. Only present if Type = AnyOf.
+	AnyOf []NestedProbe `json:"anyOf,omitempty"`
+	// Probes that all have to succeed. Only present if Type = AllOf.
+	AllOf []NestedProbe `json:"allOf,omitempty"`
+	// Probe that has to fail. Only present if Type = Not.
+	Not *NestedProbe `json:"not,omitempty"`
+}
+
+// Probe nested within a composite probe.
+// The schema of nested probes is not validated by the API server,
+// because CRD schemas can't be recursive.
+// +kubebuilder:validation:Type=object
+// +kubebuilder:pruning:PreserveUnknownFields
+type NestedProbe struct {
+	Probe `json:",inline"`
 }
 
 type ProbeType string
@@ -86,6 +410,11 @@ type ProbeType string
 const (
 	ProbeCondition   ProbeType = "Condition"
 	ProbeFieldsEqual ProbeType = "FieldsEqual"
+	ProbeFieldValue  ProbeType = "FieldValue"
+	ProbeCEL         ProbeType = "CEL"
+	ProbeAnyOf       ProbeType = "AnyOf"
+	ProbeAllOf       ProbeType = "AllOf"
+	ProbeNot         ProbeType = "Not"
 )
 
 // Condition Probe parameters.
@@ -95,6 +424,9 @@ type ProbeConditionSpec struct {
 	// Condition status to probe for.
 	// +kubebuilder:default="True"
 	Status string `json:"status"`
+	// Optional condition reason to probe for.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // Compares two fields specified by JSON Paths.
@@ -102,3 +434,34 @@ type ProbeFieldsEqualSpec struct {
 	FieldA string `json:"fieldA"`
 	FieldB string `json:"fieldB"`
 }
+
+// Checks the value of a single field specified by a JSON Path.
+// All given checks have to succeed.
+type ProbeFieldValueSpec struct {
+	// JSON Path of the field to check.
+	Field string `json:"field"`
+	// Field value must be equal to this value.
+	Equals *string `json:"equals,omitempty"`
+	// Field value must be equal to one of these values.
+	OneOf []string `json:"oneOf,omitempty"`
+	// Field value must match this regular expression.
+	Regex string `json:"regex,omitempty"`
+	// Field value must be a number greater than this value.
+	GreaterThan *int64 `json:"greaterThan,omitempty"`
+	// Field value must be a number greater than or equal to this value.
+	GreaterThanOrEqual *int64 `json:"greaterThanOrEqual,omitempty"`
+	// Field value must be a number less than this value.
+	LessThan *int64 `json:"lessThan,omitempty"`
+	// Field value must be a number less than or equal to this value.
+	LessThanOrEqual *int64 `json:"lessThanOrEqual,omitempty"`
+}
+
+// Evaluates a CEL expression against the object.
+type ProbeCELSpec struct {
+	// CEL rule to evaluate, the probed object is available as "self".
+	// The rule must evaluate to a boolean.
+	// e.g. "self.status.readyReplicas >= self.spec.replicas"
+	Rule string `json:"rule"`
+	// Message to report when the rule evaluates to false.
+	Message string `json:"message,omitempty"`
+}
@@ -13,6 +13,17 @@ type ObjectDeploymentSpec struct {
 	Selector metav1.LabelSelector `json:"selector"`
 	// Template to create new ObjectSets from.
 	Template ObjectSetTemplate `json:"template"`
+	// Restores the template of a previous revision, when set.
+	// Cleared again, as soon as the template has been restored.
+	// +optional
+	RollbackTo *ObjectDeploymentRollback `json:"rollbackTo,omitempty"`
+}
+
+// Specifies the revision to roll back to.
+type ObjectDeploymentRollback struct {
+	// Revision of an ObjectSet of this ObjectDeployment.
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
 }
 
 // ObjectSetTemplate describes the template to create new ObjectSets from.
@@ -35,6 +46,8 @@ type ObjectDeploymentStatus struct {
 	CollisionCount *int32 `json:"collisionCount,omitempty"`
 	// Computed TemplateHash.
 	TemplateHash string `json:"templateHash,omitempty"`
+	// Revision of the newest Available ObjectSet, serving the live objects.
+	Revision int64 `json:"revision,omitempty"`
 }
 
 // ObjectDeployment Condition Types
@@ -58,6 +71,7 @@ const (
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".status.revision"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type ObjectDeployment struct {
 	metav1.TypeMeta   `json:",inline"`
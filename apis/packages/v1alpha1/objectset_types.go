@@ -8,7 +8,7 @@ import (
 type ObjectSetSpec struct {
 	// Specifies the lifecycle state of the ObjectSet.
 	// +kubebuilder:default="Active"
-	// +kubebuilder:validation:Enum=Active;Paused;Archived
+	// +kubebuilder:validation:Enum=Active;Paused;Archived;Orphaned
 	LifecycleState ObjectSetLifecycleState `json:"lifecycleState,omitempty"`
 	// Pause reconcilation of specific objects, while still reporting status.
 	PausedFor []ObjectSetPausedObject `json:"pausedFor,omitempty"`
@@ -29,6 +29,10 @@ const (
 	// which deletes all objects that are not excluded via the pausedFor property and
 	// removes itself from the owner list of all other objects previously under management.
 	ObjectSetLifecycleStateArchived ObjectSetLifecycleState = "Archived"
+	// "Orphaned" disables reconcilation like "Archived",
+	// but deletes nothing and instead removes itself from the owner list of all objects,
+	// handing them over to another ObjectSet or tool.
+	ObjectSetLifecycleStateOrphaned ObjectSetLifecycleState = "Orphaned"
 )
 
 // ObjectSetStatus defines the observed state of a ObjectSet
@@ -41,6 +45,13 @@ type ObjectSetStatus struct {
 	Phase ObjectSetStatusPhase `json:"phase,omitempty"`
 	// List of objects, the controller has paused reconcilation on.
 	PausedFor []ObjectSetPausedObject `json:"pausedFor,omitempty"`
+	// Status of each reconcile phase.
+	Phases []ObjectPhaseStatus `json:"phases,omitempty"`
+	// Summary of drift detected on managed objects
+	// of phases reconciled in-process.
+	Drift *ObjectSetDriftStatus `json:"drift,omitempty"`
+	// Results of hooks run for this revision.
+	Hooks []HookStatus `json:"hooks,omitempty"`
 }
 
 // ObjectSet Condition Types
@@ -51,6 +62,19 @@ const (
 	// Succeeded condition is only set once,
 	// after a ObjectSet became Available for the first time.
 	ObjectSetSucceeded = "Succeeded"
+	// ProbesInvalid condition is True when the readiness probes are misconfigured.
+	// Reconcilation is stopped until the probes are fixed.
+	ObjectSetProbesInvalid = "ProbesInvalid"
+	// PausedForInvalid condition is True when label selectors of paused objects are invalid.
+	// Reconcilation is stopped until the selectors are fixed,
+	// so objects meant to be paused are not changed.
+	ObjectSetPausedForInvalid = "PausedForInvalid"
+	// AdoptionConflict condition is True when pre-existing objects
+	// could not be adopted due to the adoption policy.
+	ObjectSetAdoptionConflict = "AdoptionConflict"
+	// ImmutableFieldConflict condition is True when objects could not be updated,
+	// because the change touches immutable fields and recreation is not enabled.
+	ObjectSetImmutableFieldConflict = "ImmutableFieldConflict"
 )
 
 type ObjectSetStatusPhase string
@@ -78,6 +78,11 @@ func (in *ClusterObjectDeploymentSpec) DeepCopyInto(out *ClusterObjectDeployment
 	}
 	in.Selector.DeepCopyInto(&out.Selector)
 	in.Template.DeepCopyInto(&out.Template)
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(ObjectDeploymentRollback)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterObjectDeploymentSpec.
@@ -241,7 +246,9 @@ func (in *ClusterObjectSetPhaseSpec) DeepCopyInto(out *ClusterObjectSetPhaseSpec
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ReadinessProbes != nil {
 		in, out := &in.ReadinessProbes, &out.ReadinessProbes
@@ -276,7 +283,21 @@ func (in *ClusterObjectSetPhaseStatus) DeepCopyInto(out *ClusterObjectSetPhaseSt
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]ObjectPhaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(ObjectSetDriftStatus)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -360,7 +381,9 @@ func (in *ClusterObjectSetSpec) DeepCopyInto(out *ClusterObjectSetSpec) {
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.ObjectSetTemplateSpec.DeepCopyInto(&out.ObjectSetTemplateSpec)
 }
@@ -388,7 +411,28 @@ func (in *ClusterObjectSetStatus) DeepCopyInto(out *ClusterObjectSetStatus) {
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]ObjectPhaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(ObjectSetDriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]HookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -499,6 +543,41 @@ func (in *ClusterPackageStatus) DeepCopy() *ClusterPackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HookStatus) DeepCopyInto(out *HookStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HookStatus.
+func (in *HookStatus) DeepCopy() *HookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NestedProbe) DeepCopyInto(out *NestedProbe) {
+	*out = *in
+	in.Probe.DeepCopyInto(&out.Probe)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedProbe.
+func (in *NestedProbe) DeepCopy() *NestedProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(NestedProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectDeployment) DeepCopyInto(out *ObjectDeployment) {
 	*out = *in
@@ -558,6 +637,21 @@ func (in *ObjectDeploymentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectDeploymentRollback) DeepCopyInto(out *ObjectDeploymentRollback) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectDeploymentRollback.
+func (in *ObjectDeploymentRollback) DeepCopy() *ObjectDeploymentRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectDeploymentRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectDeploymentSpec) DeepCopyInto(out *ObjectDeploymentSpec) {
 	*out = *in
@@ -568,6 +662,11 @@ func (in *ObjectDeploymentSpec) DeepCopyInto(out *ObjectDeploymentSpec) {
 	}
 	in.Selector.DeepCopyInto(&out.Selector)
 	in.Template.DeepCopyInto(&out.Template)
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(ObjectDeploymentRollback)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectDeploymentSpec.
@@ -617,6 +716,21 @@ func (in *ObjectPhase) DeepCopyInto(out *ObjectPhase) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Slices != nil {
+		in, out := &in.Slices, &out.Slices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecreateOnImmutableChange != nil {
+		in, out := &in.RecreateOnImmutableChange, &out.RecreateOnImmutableChange
+		*out = new(RecreateOnImmutableChange)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectPhase.
@@ -629,6 +743,55 @@ func (in *ObjectPhase) DeepCopy() *ObjectPhase {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectPhaseFailedObject) DeepCopyInto(out *ObjectPhaseFailedObject) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectPhaseFailedObject.
+func (in *ObjectPhaseFailedObject) DeepCopy() *ObjectPhaseFailedObject {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectPhaseFailedObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectPhaseStatus) DeepCopyInto(out *ObjectPhaseStatus) {
+	*out = *in
+	if in.FailedObjects != nil {
+		in, out := &in.FailedObjects, &out.FailedObjects
+		*out = make([]ObjectPhaseFailedObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]ObjectPhaseFailedObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BlockedBy != nil {
+		in, out := &in.BlockedBy, &out.BlockedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectPhaseStatus.
+func (in *ObjectPhaseStatus) DeepCopy() *ObjectPhaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectPhaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSet) DeepCopyInto(out *ObjectSet) {
 	*out = *in
@@ -656,6 +819,35 @@ func (in *ObjectSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSetDriftStatus) DeepCopyInto(out *ObjectSetDriftStatus) {
+	*out = *in
+	if in.LastDetectionTime != nil {
+		in, out := &in.LastDetectionTime, &out.LastDetectionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]ObjectPhaseFailedObject, len(*in))
+		copy(*out, *in)
+	}
+	if in.ObjectHashes != nil {
+		in, out := &in.ObjectHashes, &out.ObjectHashes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetDriftStatus.
+func (in *ObjectSetDriftStatus) DeepCopy() *ObjectSetDriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSetDriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSetList) DeepCopyInto(out *ObjectSetList) {
 	*out = *in
@@ -692,6 +884,11 @@ func (in *ObjectSetList) DeepCopyObject() runtime.Object {
 func (in *ObjectSetObject) DeepCopyInto(out *ObjectSetObject) {
 	*out = *in
 	in.Object.DeepCopyInto(&out.Object)
+	if in.CompressedObject != nil {
+		in, out := &in.CompressedObject, &out.CompressedObject
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetObject.
@@ -707,6 +904,11 @@ func (in *ObjectSetObject) DeepCopy() *ObjectSetObject {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectSetPausedObject) DeepCopyInto(out *ObjectSetPausedObject) {
 	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSetPausedObject.
@@ -784,7 +986,9 @@ func (in *ObjectSetPhaseSpec) DeepCopyInto(out *ObjectSetPhaseSpec) {
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ReadinessProbes != nil {
 		in, out := &in.ReadinessProbes, &out.ReadinessProbes
@@ -819,7 +1023,21 @@ func (in *ObjectSetPhaseStatus) DeepCopyInto(out *ObjectSetPhaseStatus) {
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]ObjectPhaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(ObjectSetDriftStatus)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -926,7 +1144,9 @@ func (in *ObjectSetSpec) DeepCopyInto(out *ObjectSetSpec) {
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.ObjectSetTemplateSpec.DeepCopyInto(&out.ObjectSetTemplateSpec)
 }
@@ -954,7 +1174,28 @@ func (in *ObjectSetStatus) DeepCopyInto(out *ObjectSetStatus) {
 	if in.PausedFor != nil {
 		in, out := &in.PausedFor, &out.PausedFor
 		*out = make([]ObjectSetPausedObject, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]ObjectPhaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(ObjectSetDriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = make([]HookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1088,6 +1329,37 @@ func (in *PackageProbeKindSpec) DeepCopy() *PackageProbeKindSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageProbeLabelSpec) DeepCopyInto(out *PackageProbeLabelSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageProbeLabelSpec.
+func (in *PackageProbeLabelSpec) DeepCopy() *PackageProbeLabelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageProbeLabelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageProbeNameSpec) DeepCopyInto(out *PackageProbeNameSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageProbeNameSpec.
+func (in *PackageProbeNameSpec) DeepCopy() *PackageProbeNameSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageProbeNameSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageSourceSpec) DeepCopyInto(out *PackageSourceSpec) {
 	*out = *in
@@ -1159,6 +1431,35 @@ func (in *Probe) DeepCopyInto(out *Probe) {
 		*out = new(ProbeFieldsEqualSpec)
 		**out = **in
 	}
+	if in.FieldValue != nil {
+		in, out := &in.FieldValue, &out.FieldValue
+		*out = new(ProbeFieldValueSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CEL != nil {
+		in, out := &in.CEL, &out.CEL
+		*out = new(ProbeCELSpec)
+		**out = **in
+	}
+	if in.AnyOf != nil {
+		in, out := &in.AnyOf, &out.AnyOf
+		*out = make([]NestedProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllOf != nil {
+		in, out := &in.AllOf, &out.AllOf
+		*out = make([]NestedProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Not != nil {
+		in, out := &in.Not, &out.Not
+		*out = new(NestedProbe)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
@@ -1171,6 +1472,21 @@ func (in *Probe) DeepCopy() *Probe {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeCELSpec) DeepCopyInto(out *ProbeCELSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeCELSpec.
+func (in *ProbeCELSpec) DeepCopy() *ProbeCELSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeCELSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProbeConditionSpec) DeepCopyInto(out *ProbeConditionSpec) {
 	*out = *in
@@ -1186,6 +1502,51 @@ func (in *ProbeConditionSpec) DeepCopy() *ProbeConditionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeFieldValueSpec) DeepCopyInto(out *ProbeFieldValueSpec) {
+	*out = *in
+	if in.Equals != nil {
+		in, out := &in.Equals, &out.Equals
+		*out = new(string)
+		**out = **in
+	}
+	if in.OneOf != nil {
+		in, out := &in.OneOf, &out.OneOf
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GreaterThan != nil {
+		in, out := &in.GreaterThan, &out.GreaterThan
+		*out = new(int64)
+		**out = **in
+	}
+	if in.GreaterThanOrEqual != nil {
+		in, out := &in.GreaterThanOrEqual, &out.GreaterThanOrEqual
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LessThan != nil {
+		in, out := &in.LessThan, &out.LessThan
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LessThanOrEqual != nil {
+		in, out := &in.LessThanOrEqual, &out.LessThanOrEqual
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeFieldValueSpec.
+func (in *ProbeFieldValueSpec) DeepCopy() *ProbeFieldValueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeFieldValueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProbeFieldsEqualSpec) DeepCopyInto(out *ProbeFieldsEqualSpec) {
 	*out = *in
@@ -1209,6 +1570,16 @@ func (in *ProbeSelector) DeepCopyInto(out *ProbeSelector) {
 		*out = new(PackageProbeKindSpec)
 		**out = **in
 	}
+	if in.Label != nil {
+		in, out := &in.Label, &out.Label
+		*out = new(PackageProbeLabelSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(PackageProbeNameSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeSelector.
@@ -1220,3 +1591,18 @@ func (in *ProbeSelector) DeepCopy() *ProbeSelector {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecreateOnImmutableChange) DeepCopyInto(out *RecreateOnImmutableChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecreateOnImmutableChange.
+func (in *RecreateOnImmutableChange) DeepCopy() *RecreateOnImmutableChange {
+	if in == nil {
+		return nil
+	}
+	out := new(RecreateOnImmutableChange)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -111,14 +111,16 @@ func main() {
 	// Handover
 	if err = (handover.NewHandoverController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("Handover"),
-		mgr.GetScheme(), dynamicClient, discoveryClient,
+		mgr.GetScheme(), mgr.GetEventRecorderFor("coordination-operator"),
+		dynamicClient, discoveryClient,
 	)).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Handover")
 		os.Exit(1)
 	}
 	if err = (handover.NewClusterHandoverController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ClusterHandover"),
-		mgr.GetScheme(), dynamicClient, discoveryClient,
+		mgr.GetScheme(), mgr.GetEventRecorderFor("coordination-operator"),
+		dynamicClient, discoveryClient,
 	)).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterHandover")
 		os.Exit(1)
@@ -127,14 +129,16 @@ func main() {
 	// Adoption
 	if err = (adoption.NewAdoptionController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("Adoption"),
-		mgr.GetScheme(), dynamicClient, discoveryClient,
+		mgr.GetScheme(), mgr.GetEventRecorderFor("coordination-operator"),
+		dynamicClient, discoveryClient,
 	)).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Adoption")
 		os.Exit(1)
 	}
 	if err = (adoption.NewClusterAdoptionController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ClusterAdoption"),
-		mgr.GetScheme(), dynamicClient, discoveryClient,
+		mgr.GetScheme(), mgr.GetEventRecorderFor("coordination-operator"),
+		dynamicClient, discoveryClient,
 	)).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterAdoption")
 		os.Exit(1)
@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	packageapis "github.com/thetechnick/package-operator/apis"
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers/packages/objectdeployments"
 	"github.com/thetechnick/package-operator/internal/controllers/packages/objectsetphases"
 	"github.com/thetechnick/package-operator/internal/controllers/packages/objectsets"
@@ -41,6 +42,8 @@ type opts struct {
 	enableLeaderElection bool
 	namespace            string
 	probeAddr            string
+	defaultApplyMode     string
+	phaseConcurrency     int
 }
 
 func main() {
@@ -53,6 +56,11 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&opts.probeAddr, "health-probe-bind-address", ":8081",
 		"The address the probe endpoint binds to.")
+	flag.StringVar(&opts.defaultApplyMode, "default-apply-mode",
+		string(packagesv1alpha1.ObjectSetApplyModeMergePatch),
+		"Apply mode for ObjectSets not specifying one: MergePatch or ServerSideApply.")
+	flag.IntVar(&opts.phaseConcurrency, "phase-concurrency", 1,
+		"Number of objects within a phase reconciled concurrently, if the phase does not specify it.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -64,6 +72,15 @@ func main() {
 }
 
 func run(opts opts) error {
+	defaultApplyMode := packagesv1alpha1.ObjectSetApplyMode(opts.defaultApplyMode)
+	if defaultApplyMode != packagesv1alpha1.ObjectSetApplyModeMergePatch &&
+		defaultApplyMode != packagesv1alpha1.ObjectSetApplyModeServerSideApply {
+		return fmt.Errorf("invalid default apply mode %q", opts.defaultApplyMode)
+	}
+	if opts.phaseConcurrency < 1 {
+		return fmt.Errorf("phase concurrency must be at least 1, got %d", opts.phaseConcurrency)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                     scheme,
 		MetricsBindAddress:         opts.metricsAddr,
@@ -136,6 +153,7 @@ func run(opts opts) error {
 	if err = (objectsets.NewObjectSetController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ObjectSet"),
 		mgr.GetScheme(), dw,
+		mgr.GetEventRecorderFor("package-operator"), defaultApplyMode, opts.phaseConcurrency,
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for ObjectSet: %w", err)
 
@@ -143,6 +161,7 @@ func run(opts opts) error {
 	if err = (objectsets.NewClusterObjectSetController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ClusterObjectSet"),
 		mgr.GetScheme(), dw,
+		mgr.GetEventRecorderFor("package-operator"), defaultApplyMode, opts.phaseConcurrency,
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for ClusterObjectSet: %w", err)
 
@@ -154,6 +173,8 @@ func run(opts opts) error {
 		mgr.GetClient(), mgr.GetClient(),
 		ctrl.Log.WithName("controllers").WithName("ObjectSetPhase"),
 		mgr.GetScheme(), dw,
+		mgr.GetEventRecorderFor("package-operator"), mgr.GetEventRecorderFor("package-operator"),
+		defaultApplyMode, opts.phaseConcurrency,
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for ObjectSetPhase: %w", err)
 
@@ -163,6 +184,8 @@ func run(opts opts) error {
 		mgr.GetClient(), mgr.GetClient(),
 		ctrl.Log.WithName("controllers").WithName("ClusterObjectSetPhase"),
 		mgr.GetScheme(), dw,
+		mgr.GetEventRecorderFor("package-operator"), mgr.GetEventRecorderFor("package-operator"),
+		defaultApplyMode, opts.phaseConcurrency,
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for ClusterObjectSetPhase: %w", err)
 
@@ -171,14 +194,14 @@ func run(opts opts) error {
 	// ObjectDeployment
 	if err = (objectdeployments.NewObjectDeploymentController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ObjectDeployment"),
-		mgr.GetScheme(),
+		mgr.GetScheme(), mgr.GetEventRecorderFor("package-operator"),
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for ObjectDeployment: %w", err)
 
 	}
 	if err = (objectdeployments.NewClusterObjectDeploymentController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ClusterObjectDeployment"),
-		mgr.GetScheme(),
+		mgr.GetScheme(), mgr.GetEventRecorderFor("package-operator"),
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for ClusterObjectDeployment: %w", err)
 
@@ -187,13 +210,13 @@ func run(opts opts) error {
 	// Package
 	if err = (packages.NewPackageController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("Package"),
-		mgr.GetScheme(), opts.namespace,
+		mgr.GetScheme(), mgr.GetEventRecorderFor("package-operator"), opts.namespace,
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for Package: %w", err)
 	}
 	if err = (packages.NewClusterPackageController(
 		mgr.GetClient(), ctrl.Log.WithName("controllers").WithName("ClusterPackage"),
-		mgr.GetScheme(), opts.namespace,
+		mgr.GetScheme(), mgr.GetEventRecorderFor("package-operator"), opts.namespace,
 	).SetupWithManager(mgr)); err != nil {
 		return fmt.Errorf("unable to create controller for ClusterPackage: %w", err)
 	}
@@ -8,10 +8,14 @@ import (
 	"net/http/pprof"
 	"os"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -19,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	packageapis "github.com/thetechnick/package-operator/apis"
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers/packages/objectsetphases"
 	"github.com/thetechnick/package-operator/internal/dynamicwatcher"
 	"github.com/thetechnick/package-operator/internal/ownerhandling"
@@ -44,6 +49,8 @@ func main() {
 		enableLeaderElection        bool
 		namespace                   string
 		class                       string
+		defaultApplyMode            string
+		phaseConcurrency            int
 	)
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&pprofAddr, "pprof-addr", "", "The address the pprof web endpoint binds to.")
@@ -53,6 +60,11 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&defaultApplyMode, "default-apply-mode",
+		string(packagesv1alpha1.ObjectSetApplyModeMergePatch),
+		"Apply mode for ObjectSetPhases not specifying one: MergePatch or ServerSideApply.")
+	flag.IntVar(&phaseConcurrency, "phase-concurrency", 1,
+		"Number of objects within a phase reconciled concurrently, if the phase does not specify it.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -62,6 +74,8 @@ func main() {
 		targetClusterKubeconfigFile,
 		enableLeaderElection,
 		namespace, class,
+		packagesv1alpha1.ObjectSetApplyMode(defaultApplyMode),
+		phaseConcurrency,
 	); err != nil {
 		setupLog.Error(err, "run manager")
 		os.Exit(1)
@@ -74,7 +88,17 @@ func run(
 	targetClusterKubeconfigFile string,
 	enableLeaderElection bool,
 	namespace, class string,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	phaseConcurrency int,
 ) error {
+	if defaultApplyMode != packagesv1alpha1.ObjectSetApplyModeMergePatch &&
+		defaultApplyMode != packagesv1alpha1.ObjectSetApplyModeServerSideApply {
+		return fmt.Errorf("invalid default apply mode %q", defaultApplyMode)
+	}
+	if phaseConcurrency < 1 {
+		return fmt.Errorf("phase concurrency must be at least 1, got %d", phaseConcurrency)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                     scheme,
 		MetricsBindAddress:         metricsAddr,
@@ -147,6 +171,19 @@ func run(
 		os.Exit(1)
 	}
 
+	// Events on managed objects are recorded in the target cluster.
+	targetKubeClient, err := kubernetes.NewForConfig(targetCfg)
+	if err != nil {
+		return fmt.Errorf("creating target cluster kube client: %w", err)
+	}
+	targetEventBroadcaster := record.NewBroadcaster()
+	targetEventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: targetKubeClient.CoreV1().Events(""),
+	})
+	defer targetEventBroadcaster.Shutdown()
+	targetRecorder := targetEventBroadcaster.NewRecorder(
+		targetScheme, corev1.EventSource{Component: "package-phase-operator"})
+
 	// Dynamic Watcher
 	dw := dynamicwatcher.New(
 		ctrl.Log.WithName("DynamicWatcher"),
@@ -159,6 +196,8 @@ func run(
 		ctrl.Log.WithName("controllers").WithName("ObjectSetPhase"),
 		mgr.GetScheme(),
 		&clusterLevelEnforcingDynamicWatcher{dw},
+		mgr.GetEventRecorderFor("package-phase-operator"), targetRecorder,
+		defaultApplyMode, phaseConcurrency,
 	).SetupWithManager(mgr)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ObjectSetPhase")
 		os.Exit(1)
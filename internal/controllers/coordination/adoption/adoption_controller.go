@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -29,6 +30,7 @@ type GenericAdoptionController struct {
 	client          client.Client
 	log             logr.Logger
 	scheme          *runtime.Scheme
+	recorder        record.EventRecorder
 	dynamicClient   dynamic.Interface
 	discoveryClient *discovery.DiscoveryClient
 	reconciler      []reconciler
@@ -42,30 +44,33 @@ type reconciler interface {
 
 func NewAdoptionController(
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, dynamicClient dynamic.Interface,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	dynamicClient dynamic.Interface,
 	discoveryClient *discovery.DiscoveryClient,
 ) *GenericAdoptionController {
 	return newGenericAdoptionController(
 		coordinationv1alpha1.GroupVersion.WithKind("Adoption"),
-		c, log, scheme, dynamicClient, discoveryClient,
+		c, log, scheme, recorder, dynamicClient, discoveryClient,
 	)
 }
 
 func NewClusterAdoptionController(
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, dynamicClient dynamic.Interface,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	dynamicClient dynamic.Interface,
 	discoveryClient *discovery.DiscoveryClient,
 ) *GenericAdoptionController {
 	return newGenericAdoptionController(
 		coordinationv1alpha1.GroupVersion.WithKind("ClusterAdoption"),
-		c, log, scheme, dynamicClient, discoveryClient,
+		c, log, scheme, recorder, dynamicClient, discoveryClient,
 	)
 }
 
 func newGenericAdoptionController(
 	gvk schema.GroupVersionKind,
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, dynamicClient dynamic.Interface,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	dynamicClient dynamic.Interface,
 	discoveryClient *discovery.DiscoveryClient,
 ) *GenericAdoptionController {
 	return &GenericAdoptionController{
@@ -73,12 +78,13 @@ func newGenericAdoptionController(
 		client:          c,
 		log:             log,
 		scheme:          scheme,
+		recorder:        recorder,
 		dynamicClient:   dynamicClient,
 		discoveryClient: discoveryClient,
 
 		reconciler: []reconciler{
-			&StaticAdoptionReconciler{client: c},
-			&RoundRobinAdoptionReconciler{client: c},
+			&StaticAdoptionReconciler{client: c, recorder: recorder},
+			&RoundRobinAdoptionReconciler{client: c, recorder: recorder},
 		},
 	}
 }
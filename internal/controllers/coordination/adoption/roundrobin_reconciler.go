@@ -8,12 +8,14 @@ import (
 	"github.com/thetechnick/package-operator/internal/controllers/coordination"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type RoundRobinAdoptionReconciler struct {
-	client client.Client
+	client   client.Client
+	recorder record.EventRecorder
 }
 
 func (r *RoundRobinAdoptionReconciler) Reconcile(
@@ -77,6 +79,8 @@ func (r *RoundRobinAdoptionReconciler) Reconcile(
 			return ctrl.Result{}, fmt.Errorf("setting labels: %w", err)
 		}
 
+		recordAdopted(r.recorder, adoption, &obj)
+
 		// track last committed index
 		adoption.SetRoundRobinStatus(&coordinationv1alpha1.AdoptionRoundRobinStatus{
 			LastIndex: rrIndex,
@@ -5,14 +5,18 @@ import (
 	"fmt"
 
 	"github.com/thetechnick/package-operator/internal/controllers/coordination"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type StaticAdoptionReconciler struct {
-	client client.Client
+	client   client.Client
+	recorder record.EventRecorder
 }
 
 func (r *StaticAdoptionReconciler) Reconcile(
@@ -54,11 +58,22 @@ func (r *StaticAdoptionReconciler) Reconcile(
 		if err := r.client.Update(ctx, &obj); err != nil {
 			return ctrl.Result{}, fmt.Errorf("setting labels: %w", err)
 		}
+		recordAdopted(r.recorder, adoption, &obj)
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// Records an Event on the Adoption and the newly labeled object.
+func recordAdopted(
+	recorder record.EventRecorder, adoption genericAdoption, obj *unstructured.Unstructured,
+) {
+	recorder.Eventf(adoption.ClientObject(), corev1.EventTypeNormal, "Adopted",
+		"%s %s was labeled.", obj.GetKind(), obj.GetName())
+	recorder.Eventf(obj, corev1.EventTypeNormal, "Adopted",
+		"Object was labeled by %s.", adoption.ClientObject().GetName())
+}
+
 func negativeLabelSelectorFromLabels(specLabels map[string]string) (labels.Selector, error) {
 	// Build selector
 	var requirements []labels.Requirement
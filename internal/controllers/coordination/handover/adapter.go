@@ -24,6 +24,7 @@ type genericHandover interface {
 	GetProcessing() []coordinationv1alpha1.HandoverRef
 	GetRelabelSpec() *coordinationv1alpha1.HandoverStrategyRelabelSpec
 	SetProcessing(processing []coordinationv1alpha1.HandoverRef)
+	GetStats() coordinationv1alpha1.HandoverStatusStats
 	SetStats(stats coordinationv1alpha1.HandoverStatusStats)
 }
 
@@ -76,6 +77,10 @@ func (a *GenericHandover) GetRelabelSpec() *coordinationv1alpha1.HandoverStrateg
 	return a.Spec.Strategy.Relabel
 }
 
+func (a *GenericHandover) GetStats() coordinationv1alpha1.HandoverStatusStats {
+	return a.Status.Stats
+}
+
 func (a *GenericHandover) SetStats(stats coordinationv1alpha1.HandoverStatusStats) {
 	a.Status.Stats = stats
 }
@@ -124,6 +129,10 @@ func (a *GenericClusterHandover) GetRelabelSpec() *coordinationv1alpha1.Handover
 	return a.Spec.Strategy.Relabel
 }
 
+func (a *GenericClusterHandover) GetStats() coordinationv1alpha1.HandoverStatusStats {
+	return a.Status.Stats
+}
+
 func (a *GenericClusterHandover) SetStats(stats coordinationv1alpha1.HandoverStatusStats) {
 	a.Status.Stats = stats
 }
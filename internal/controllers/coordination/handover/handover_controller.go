@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/thetechnick/package-operator/internal/controllers"
 	"github.com/thetechnick/package-operator/internal/controllers/coordination"
 	"github.com/thetechnick/package-operator/internal/dynamicwatcher"
+	"github.com/thetechnick/package-operator/internal/metrics"
 )
 
 // Generic reconciler for both Handover and ClusterHandover objects.
@@ -26,6 +28,7 @@ type GenericHandoverController struct {
 	client          client.Client
 	log             logr.Logger
 	scheme          *runtime.Scheme
+	recorder        record.EventRecorder
 	dynamicClient   dynamic.Interface
 	discoveryClient *discovery.DiscoveryClient
 	reconciler      []reconciler
@@ -39,30 +42,33 @@ type reconciler interface {
 
 func NewHandoverController(
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, dynamicClient dynamic.Interface,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	dynamicClient dynamic.Interface,
 	discoveryClient *discovery.DiscoveryClient,
 ) *GenericHandoverController {
 	return NewGenericHandoverController(
 		coordinationv1alpha1.GroupVersion.WithKind("Handover"),
-		c, log, scheme, dynamicClient, discoveryClient,
+		c, log, scheme, recorder, dynamicClient, discoveryClient,
 	)
 }
 
 func NewClusterHandoverController(
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, dynamicClient dynamic.Interface,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	dynamicClient dynamic.Interface,
 	discoveryClient *discovery.DiscoveryClient,
 ) *GenericHandoverController {
 	return NewGenericHandoverController(
 		coordinationv1alpha1.GroupVersion.WithKind("ClusterHandover"),
-		c, log, scheme, dynamicClient, discoveryClient,
+		c, log, scheme, recorder, dynamicClient, discoveryClient,
 	)
 }
 
 func NewGenericHandoverController(
 	gvk schema.GroupVersionKind,
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, dynamicClient dynamic.Interface,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	dynamicClient dynamic.Interface,
 	discoveryClient *discovery.DiscoveryClient,
 ) *GenericHandoverController {
 	return &GenericHandoverController{
@@ -71,10 +77,11 @@ func NewGenericHandoverController(
 		client:          c,
 		log:             log,
 		scheme:          scheme,
+		recorder:        recorder,
 		dynamicClient:   dynamicClient,
 		discoveryClient: discoveryClient,
 		reconciler: []reconciler{
-			&relabelReconciler{client: c},
+			&relabelReconciler{client: c, recorder: recorder},
 		},
 	}
 }
@@ -118,9 +125,23 @@ func (c *GenericHandoverController) Reconcile(
 	}
 
 	handover.UpdatePhase()
+	c.recordProgress(handover)
 	return res, c.client.Status().Update(ctx, handover.ClientObject())
 }
 
+// States of objects reported by the HandoverObjects metric.
+var handoverObjectStates = []string{"Found", "Updated", "Available"}
+
+func (c *GenericHandoverController) recordProgress(handover genericHandover) {
+	obj := handover.ClientObject()
+	stats := handover.GetStats()
+	for i, count := range []int32{stats.Found, stats.Updated, stats.Available} {
+		metrics.HandoverObjects.WithLabelValues(
+			c.gvk.Kind, obj.GetNamespace(), obj.GetName(), handoverObjectStates[i],
+		).Set(float64(count))
+	}
+}
+
 func (c *GenericHandoverController) SetupWithManager(
 	mgr ctrl.Manager) error {
 	c.dw = dynamicwatcher.New(
@@ -154,6 +175,11 @@ func (c *GenericHandoverController) newOperand() genericHandover {
 func (c *GenericHandoverController) handleDeletion(
 	ctx context.Context, handover genericHandover,
 ) error {
+	obj := handover.ClientObject()
+	for _, state := range handoverObjectStates {
+		metrics.HandoverObjects.DeleteLabelValues(
+			c.gvk.Kind, obj.GetNamespace(), obj.GetName(), state)
+	}
 	return controllers.HandleCommonDeletion(
 		ctx, handover.ClientObject(), c.client, c.dw, coordination.CacheFinalizer)
 }
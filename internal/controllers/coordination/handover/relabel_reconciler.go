@@ -8,19 +8,22 @@ import (
 	"github.com/thetechnick/package-operator/internal/controllers"
 	"github.com/thetechnick/package-operator/internal/controllers/coordination"
 	internalprobe "github.com/thetechnick/package-operator/internal/probe"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/jsonpath"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type relabelReconciler struct {
-	client client.Client
+	client   client.Client
+	recorder record.EventRecorder
 }
 
 func (r *relabelReconciler) Reconcile(
@@ -32,10 +35,22 @@ func (r *relabelReconciler) Reconcile(
 	gvk, objType, objListType := coordination.UnstructuredFromTargetAPI(handover.GetTargetAPI())
 	relabelSpec := handover.GetRelabelSpec()
 
-	combinedProbe := internalprobe.Parse(handover.GetProbes())
+	combinedProbe, err := internalprobe.Parse(handover.GetProbes())
+	if err != nil {
+		meta.SetStatusCondition(handover.GetConditions(), metav1.Condition{
+			Type:               coordinationv1alpha1.HandoverProbesInvalid,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: handover.ClientObject().GetGeneration(),
+			Reason:             "ProbesInvalid",
+			Message:            err.Error(),
+		})
+		// Nothing we can do until the spec is fixed.
+		return ctrl.Result{}, nil
+	}
+	meta.RemoveStatusCondition(handover.GetConditions(), coordinationv1alpha1.HandoverProbesInvalid)
 
 	// Handle processing objects
-	stillProcessing, err := r.handleAllProcessing(ctx, *relabelSpec,
+	stillProcessing, err := r.handleAllProcessing(ctx, handover, *relabelSpec,
 		objType, combinedProbe, handover.GetProcessing())
 	if err != nil {
 		return ctrl.Result{}, err
@@ -109,6 +124,10 @@ func (r *relabelReconciler) Reconcile(
 	handover.SetStats(stats)
 
 	if stats.Found == stats.Updated && len(processing) == 0 {
+		if !meta.IsStatusConditionTrue(*handover.GetConditions(), coordinationv1alpha1.HandoverCompleted) {
+			r.recorder.Event(handover.ClientObject(), corev1.EventTypeNormal, "HandoverCompleted",
+				"All found objects have been re-labeled.")
+		}
 		meta.SetStatusCondition(handover.GetConditions(), metav1.Condition{
 			Type:               coordinationv1alpha1.HandoverCompleted,
 			Status:             metav1.ConditionTrue,
@@ -134,6 +153,7 @@ func (r *relabelReconciler) Reconcile(
 
 func (r *relabelReconciler) handleAllProcessing(
 	ctx context.Context,
+	handover genericHandover,
 	relabelSpec coordinationv1alpha1.HandoverStrategyRelabelSpec,
 	objType *unstructured.Unstructured,
 	probe internalprobe.Interface,
@@ -141,7 +161,7 @@ func (r *relabelReconciler) handleAllProcessing(
 ) (stillProcessing []coordinationv1alpha1.HandoverRef, err error) {
 	for _, handoverRef := range processing {
 		finished, err := r.handleSingleProcessing(
-			ctx, relabelSpec, objType, probe, handoverRef)
+			ctx, handover, relabelSpec, objType, probe, handoverRef)
 		if err != nil {
 			return stillProcessing, err
 		}
@@ -154,6 +174,7 @@ func (r *relabelReconciler) handleAllProcessing(
 
 func (r *relabelReconciler) handleSingleProcessing(
 	ctx context.Context,
+	handover genericHandover,
 	relabelSpec coordinationv1alpha1.HandoverStrategyRelabelSpec,
 	objType *unstructured.Unstructured,
 	probe internalprobe.Interface,
@@ -218,6 +239,10 @@ func (r *relabelReconciler) handleSingleProcessing(
 		return false, nil
 	}
 
+	r.recorder.Eventf(handover.ClientObject(), corev1.EventTypeNormal, "HandoverStepCompleted",
+		"%s %s was handed over to %q.", processingObj.GetKind(), processingObj.GetName(), relabelSpec.ToValue)
+	r.recorder.Eventf(processingObj, corev1.EventTypeNormal, "HandoverStepCompleted",
+		"Object was handed over to %q.", relabelSpec.ToValue)
 	return true, nil
 }
 
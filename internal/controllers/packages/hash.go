@@ -2,12 +2,17 @@ package packages
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"reflect"
 
 	"github.com/davecgh/go-spew/spew"
 	"k8s.io/apimachinery/pkg/util/rand"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	legacyv1alpha1 "github.com/thetechnick/package-operator/internal/controllers/packages/internal/legacy/v1alpha1"
 )
 
 // ComputeHash returns a hash value calculated from pod template and
@@ -15,7 +20,7 @@ import (
 // avoid bad words.
 func ComputeHash(obj interface{}, collisionCount *int32) string {
 	hasher := fnv.New32a()
-	DeepHashObject(hasher, obj)
+	DeepHashObject(hasher, hashableObject(obj))
 
 	// Add collisionCount in the hash if it exists.
 	if collisionCount != nil {
@@ -28,6 +33,176 @@ func ComputeHash(obj interface{}, collisionCount *int32) string {
 	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
 }
 
+// Returns ObjectSetTemplates in a form that keeps their hash stable.
+// Compressed objects are decompressed, so compressing objects does not lead to a new hash.
+// Templates only using fields of earlier versions are hashed like those versions did,
+// so upgrading package-operator does not roll out new revisions.
+// Other templates are hashed via their JSON representation omitting unset fields,
+// so adding optional fields to the API does not change their hash either.
+func hashableObject(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case packagesv1alpha1.ObjectSetTemplate:
+		if template, err := decompressTemplate(o); err == nil {
+			return hashableTemplate(template)
+		}
+	case *packagesv1alpha1.ObjectSetTemplate:
+		if o == nil {
+			return obj
+		}
+		if template, err := decompressTemplate(*o); err == nil {
+			hashable := hashableTemplate(template)
+			if legacy, ok := hashable.(legacyv1alpha1.ObjectSetTemplate); ok {
+				return &legacy
+			}
+			return hashable
+		}
+	}
+	// invalid objects are hashed as is and reported during reconciliation
+	return obj
+}
+
+func hashableTemplate(template packagesv1alpha1.ObjectSetTemplate) interface{} {
+	// values defaulted by the API server are equivalent to unset values.
+	if template.Spec.DriftPolicy == packagesv1alpha1.ObjectSetDriftPolicyCorrect {
+		template.Spec.DriftPolicy = ""
+	}
+	if template.Spec.AdoptionPolicy == packagesv1alpha1.ObjectSetAdoptionPolicyIfNoController {
+		template.Spec.AdoptionPolicy = ""
+	}
+
+	j, err := json.Marshal(template)
+	if err != nil {
+		return template
+	}
+	legacy := legacyTemplate(template)
+	if equalWithoutZeroValues(template, legacy) {
+		// no fields besides those of the legacy types are set.
+		return legacy
+	}
+	return string(j)
+}
+
+// Compares the JSON representation of both objects, ignoring zero-valued fields.
+// Zero values are equivalent to unset fields in the API,
+// but are serialized differently depending on "omitempty".
+func equalWithoutZeroValues(a, b interface{}) bool {
+	va, err := withoutZeroValues(a)
+	if err != nil {
+		return false
+	}
+	vb, err := withoutZeroValues(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func withoutZeroValues(obj interface{}) (interface{}, error) {
+	j, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(j, &v); err != nil {
+		return nil, err
+	}
+	return pruneZeroValues(v), nil
+}
+
+func pruneZeroValues(v interface{}) interface{} {
+	switch o := v.(type) {
+	case map[string]interface{}:
+		for key, value := range o {
+			value = pruneZeroValues(value)
+			if isZeroValue(value) {
+				delete(o, key)
+				continue
+			}
+			o[key] = value
+		}
+	case []interface{}:
+		// list items are kept, their position is significant.
+		for i := range o {
+			o[i] = pruneZeroValues(o[i])
+		}
+	}
+	return v
+}
+
+func isZeroValue(v interface{}) bool {
+	switch o := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(o) == 0
+	case []interface{}:
+		return len(o) == 0
+	case string:
+		return len(o) == 0
+	case float64:
+		return o == 0
+	case bool:
+		return !o
+	}
+	return false
+}
+
+// Projects the template onto the legacy types, dropping all fields added since.
+// nil and empty slices are kept apart, as they are hashed differently.
+func legacyTemplate(
+	template packagesv1alpha1.ObjectSetTemplate,
+) legacyv1alpha1.ObjectSetTemplate {
+	legacy := legacyv1alpha1.ObjectSetTemplate{Metadata: template.Metadata}
+	if template.Spec.Phases != nil {
+		legacy.Spec.Phases = make([]legacyv1alpha1.ObjectPhase, len(template.Spec.Phases))
+	}
+	for i, phase := range template.Spec.Phases {
+		legacyPhase := legacyv1alpha1.ObjectPhase{Name: phase.Name, Class: phase.Class}
+		if phase.Objects != nil {
+			legacyPhase.Objects = make([]legacyv1alpha1.ObjectSetObject, len(phase.Objects))
+		}
+		for j, obj := range phase.Objects {
+			legacyPhase.Objects[j] = legacyv1alpha1.ObjectSetObject{Object: obj.Object}
+		}
+		legacy.Spec.Phases[i] = legacyPhase
+	}
+
+	if template.Spec.ReadinessProbes != nil {
+		legacy.Spec.ReadinessProbes = make(
+			[]legacyv1alpha1.ObjectSetProbe, len(template.Spec.ReadinessProbes))
+	}
+	for i, probe := range template.Spec.ReadinessProbes {
+		legacyProbe := legacyv1alpha1.ObjectSetProbe{
+			Selector: legacyv1alpha1.ProbeSelector{
+				Type: legacyv1alpha1.ProbeSelectorType(probe.Selector.Type),
+			},
+		}
+		if kind := probe.Selector.Kind; kind != nil {
+			legacyProbe.Selector.Kind = &legacyv1alpha1.PackageProbeKindSpec{
+				Group: kind.Group, Kind: kind.Kind,
+			}
+		}
+		if probe.Probes != nil {
+			legacyProbe.Probes = make([]legacyv1alpha1.Probe, len(probe.Probes))
+		}
+		for j, p := range probe.Probes {
+			legacyProbe.Probes[j] = legacyv1alpha1.Probe{Type: legacyv1alpha1.ProbeType(p.Type)}
+			if cond := p.Condition; cond != nil {
+				legacyProbe.Probes[j].Condition = &legacyv1alpha1.ProbeConditionSpec{
+					Type: cond.Type, Status: cond.Status,
+				}
+			}
+			if fe := p.FieldsEqual; fe != nil {
+				legacyProbe.Probes[j].FieldsEqual = &legacyv1alpha1.ProbeFieldsEqualSpec{
+					FieldA: fe.FieldA, FieldB: fe.FieldB,
+				}
+			}
+		}
+		legacy.Spec.ReadinessProbes[i] = legacyProbe
+	}
+	return legacy
+}
+
 // DeepHashObject writes specified object to hash using the spew library
 // which follows pointers and prints actual values of the nested objects
 // ensuring the hash does not change when a pointer changes.
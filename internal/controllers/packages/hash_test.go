@@ -0,0 +1,97 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+// Template only using fields available before the API was extended.
+func newBaselineTemplate() packagesv1alpha1.ObjectSetTemplate {
+	return packagesv1alpha1.ObjectSetTemplate{
+		Metadata: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "test"},
+		},
+		Spec: packagesv1alpha1.ObjectSetTemplateSpec{
+			Phases: []packagesv1alpha1.ObjectPhase{
+				{
+					Name: "deploy",
+					Objects: []packagesv1alpha1.ObjectSetObject{{
+						Object: runtime.RawExtension{
+							Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"test"}}`),
+						},
+					}},
+				},
+				{
+					Name:  "remote",
+					Class: "remote",
+					Objects: []packagesv1alpha1.ObjectSetObject{{
+						Object: runtime.RawExtension{
+							Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"test"}}`),
+						},
+					}},
+				},
+			},
+			ReadinessProbes: []packagesv1alpha1.ObjectSetProbe{{
+				Selector: packagesv1alpha1.ProbeSelector{
+					Type: packagesv1alpha1.ProbeSelectorKind,
+					Kind: &packagesv1alpha1.PackageProbeKindSpec{Group: "apps", Kind: "Deployment"},
+				},
+				Probes: []packagesv1alpha1.Probe{
+					{
+						Type:      packagesv1alpha1.ProbeCondition,
+						Condition: &packagesv1alpha1.ProbeConditionSpec{Type: "Available", Status: "True"},
+					},
+					{
+						Type: packagesv1alpha1.ProbeFieldsEqual,
+						FieldsEqual: &packagesv1alpha1.ProbeFieldsEqualSpec{
+							FieldA: ".status.updatedReplicas", FieldB: ".status.replicas",
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestComputeHash_golden(t *testing.T) {
+	// Hashes computed by earlier versions for the baseline template.
+	// Changing them rolls out a new revision of every ObjectDeployment on upgrade.
+	const (
+		goldenHash              = "6987645d5b"
+		goldenHashWithCollision = "79665d55cc"
+	)
+	collisionCount := int32(1)
+
+	template := newBaselineTemplate()
+	assert.Equal(t, goldenHash, ComputeHash(template, nil))
+	assert.Equal(t, goldenHashWithCollision, ComputeHash(template, &collisionCount))
+
+	// fields defaulted by the API server
+	defaulted := newBaselineTemplate()
+	defaulted.Spec.DriftPolicy = packagesv1alpha1.ObjectSetDriftPolicyCorrect
+	defaulted.Spec.AdoptionPolicy = packagesv1alpha1.ObjectSetAdoptionPolicyIfNoController
+	assert.Equal(t, goldenHash, ComputeHash(defaulted, nil))
+
+	// zero-valued fields added later
+	zero := newBaselineTemplate()
+	zero.Spec.Phases[0].DependsOn = []string{}
+	zero.Spec.Phases[0].MinReadySeconds = 0
+	assert.Equal(t, goldenHash, ComputeHash(zero, nil))
+}
+
+func TestComputeHash_newFields(t *testing.T) {
+	base := newBaselineTemplate()
+	base.Spec.Phases[0].MinReadySeconds = 10
+	baseHash := ComputeHash(base, nil)
+	assert.NotEqual(t, ComputeHash(newBaselineTemplate(), nil), baseHash)
+
+	changed := newBaselineTemplate()
+	changed.Spec.Phases[0].MinReadySeconds = 20
+	assert.NotEqual(t, baseHash, ComputeHash(changed, nil))
+
+}
@@ -0,0 +1,101 @@
+package packages
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+var jobGroupKind = schema.GroupKind{Group: "batch", Kind: "Job"}
+
+// Hook Job of an ObjectSet.
+type Hook struct {
+	Type   packagesv1alpha1.HookType
+	Object *unstructured.Unstructured
+}
+
+// Returns the hooks of the given phases in order.
+// Hooks of phases reconciled by another controller are ignored.
+func PhaseHooks(phases []packagesv1alpha1.ObjectPhase) ([]Hook, error) {
+	var hooks []Hook
+	for _, phase := range phases {
+		if len(phase.Class) > 0 {
+			continue
+		}
+		for i := range phase.Objects {
+			obj, err := UnstructuredFromObjectObject(&phase.Objects[i])
+			if err != nil {
+				return nil, err
+			}
+			hookType, ok, err := getHookType(obj)
+			if err != nil {
+				return nil, fmt.Errorf("phase %q: %w", phase.Name, err)
+			}
+			if ok {
+				hooks = append(hooks, Hook{Type: hookType, Object: obj})
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// Returns the phase without hook objects of the given types,
+// or without any hook objects if no type is given.
+func WithoutHooks(
+	phase packagesv1alpha1.ObjectPhase, hookTypes ...packagesv1alpha1.HookType,
+) (packagesv1alpha1.ObjectPhase, error) {
+	if len(phase.Class) > 0 {
+		return phase, nil
+	}
+
+	objects := make([]packagesv1alpha1.ObjectSetObject, 0, len(phase.Objects))
+	for i := range phase.Objects {
+		obj, err := UnstructuredFromObjectObject(&phase.Objects[i])
+		if err != nil {
+			return phase, err
+		}
+		if hookType, ok, _ := getHookType(obj); !ok || !matchesHookType(hookType, hookTypes) {
+			objects = append(objects, phase.Objects[i])
+		}
+	}
+	phase.Objects = objects
+	return phase, nil
+}
+
+func matchesHookType(
+	hookType packagesv1alpha1.HookType, hookTypes []packagesv1alpha1.HookType,
+) bool {
+	if len(hookTypes) == 0 {
+		return true
+	}
+	for _, t := range hookTypes {
+		if t == hookType {
+			return true
+		}
+	}
+	return false
+}
+
+func getHookType(obj *unstructured.Unstructured) (packagesv1alpha1.HookType, bool, error) {
+	hookType, ok := obj.GetAnnotations()[packagesv1alpha1.HookAnnotation]
+	if !ok {
+		return "", false, nil
+	}
+
+	switch packagesv1alpha1.HookType(hookType) {
+	case packagesv1alpha1.HookPreInstall,
+		packagesv1alpha1.HookPostInstall,
+		packagesv1alpha1.HookPreUpgrade,
+		packagesv1alpha1.HookPreDelete:
+	default:
+		return "", false, fmt.Errorf("%s %q: invalid %s annotation %q",
+			obj.GetKind(), obj.GetName(), packagesv1alpha1.HookAnnotation, hookType)
+	}
+	if obj.GroupVersionKind().GroupKind() != jobGroupKind {
+		return "", false, fmt.Errorf("%s %q: hooks must be of kind Job", obj.GetKind(), obj.GetName())
+	}
+	return packagesv1alpha1.HookType(hookType), true, nil
+}
@@ -0,0 +1,58 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestPhaseHooks(t *testing.T) {
+	phase := packagesv1alpha1.ObjectPhase{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"migrate",` +
+				`"annotations":{"packages.thetechnick.ninja/hook":"pre-upgrade"}}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"backup",` +
+				`"annotations":{"packages.thetechnick.ninja/hook":"pre-delete"}}}`)}},
+		},
+	}
+
+	hooks, err := PhaseHooks([]packagesv1alpha1.ObjectPhase{phase})
+	require.NoError(t, err)
+	if assert.Len(t, hooks, 2) {
+		assert.Equal(t, packagesv1alpha1.HookPreUpgrade, hooks[0].Type)
+		assert.Equal(t, "migrate", hooks[0].Object.GetName())
+		assert.Equal(t, packagesv1alpha1.HookPreDelete, hooks[1].Type)
+		assert.Equal(t, "backup", hooks[1].Object.GetName())
+	}
+
+	withoutHooks, err := WithoutHooks(phase)
+	require.NoError(t, err)
+	assert.Equal(t, phase.Objects[:1], withoutHooks.Objects)
+
+	withoutPreDelete, err := WithoutHooks(phase, packagesv1alpha1.HookPreDelete)
+	require.NoError(t, err)
+	assert.Equal(t, phase.Objects[:2], withoutPreDelete.Objects)
+
+	// hooks in remote phases are not run by the ObjectSet
+	phase.Class = "remote"
+	hooks, err = PhaseHooks([]packagesv1alpha1.ObjectPhase{phase})
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+}
+
+func TestPhaseHooks_invalid(t *testing.T) {
+	_, err := PhaseHooks([]packagesv1alpha1.ObjectPhase{{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"migrate",` +
+				`"annotations":{"packages.thetechnick.ninja/hook":"pre-install"}}}`)}},
+		},
+	}})
+	assert.EqualError(t, err, `phase "test": Pod "migrate": hooks must be of kind Job`)
+}
@@ -0,0 +1,67 @@
+// Package v1alpha1 contains frozen copies of the ObjectSetTemplate types,
+// as they were before fields were added to them.
+// Template hashes of ObjectDeployments are computed from these types,
+// when a template only uses their fields, so existing ObjectDeployments
+// keep their template hash and don't roll out a new revision on upgrade.
+// The package name and field order are part of the hash and must not change.
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type ObjectSetTemplate struct {
+	Metadata metav1.ObjectMeta     `json:"metadata"`
+	Spec     ObjectSetTemplateSpec `json:"spec"`
+}
+
+type ObjectSetTemplateSpec struct {
+	Phases          []ObjectPhase    `json:"phases"`
+	ReadinessProbes []ObjectSetProbe `json:"readinessProbes"`
+}
+
+type ObjectPhase struct {
+	Name    string            `json:"name"`
+	Class   string            `json:"class,omitempty"`
+	Objects []ObjectSetObject `json:"objects"`
+}
+
+type ObjectSetObject struct {
+	Object runtime.RawExtension `json:"object"`
+}
+
+type ObjectSetProbe struct {
+	Probes   []Probe       `json:"probes"`
+	Selector ProbeSelector `json:"selector"`
+}
+
+type ProbeSelectorType string
+
+type ProbeSelector struct {
+	Type ProbeSelectorType     `json:"type"`
+	Kind *PackageProbeKindSpec `json:"kind,omitempty"`
+}
+
+type PackageProbeKindSpec struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+}
+
+type Probe struct {
+	Type        ProbeType             `json:"type"`
+	Condition   *ProbeConditionSpec   `json:"condition,omitempty"`
+	FieldsEqual *ProbeFieldsEqualSpec `json:"fieldsEqual,omitempty"`
+}
+
+type ProbeType string
+
+type ProbeConditionSpec struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+type ProbeFieldsEqualSpec struct {
+	FieldA string `json:"fieldA"`
+	FieldB string `json:"fieldB"`
+}
@@ -0,0 +1,98 @@
+package packages
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+// Upper bound for decompressed objects, guarding against decompression bombs.
+const maxDecompressedObjectSize = 16 * 1024 * 1024
+
+// Returns an ObjectSetObject storing the given object gzip compressed.
+func CompressObject(obj *unstructured.Unstructured) (packagesv1alpha1.ObjectSetObject, error) {
+	j, err := json.Marshal(obj)
+	if err != nil {
+		return packagesv1alpha1.ObjectSetObject{}, fmt.Errorf("marshalling object: %w", err)
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(j); err != nil {
+		return packagesv1alpha1.ObjectSetObject{}, fmt.Errorf("compressing object: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return packagesv1alpha1.ObjectSetObject{}, fmt.Errorf("compressing object: %w", err)
+	}
+	return packagesv1alpha1.ObjectSetObject{CompressedObject: buf.Bytes()}, nil
+}
+
+// Returns the raw JSON/YAML of the object, decompressing it if needed.
+func objectSetObjectRaw(packageObject *packagesv1alpha1.ObjectSetObject) ([]byte, error) {
+	if len(packageObject.CompressedObject) == 0 {
+		return packageObject.Object.Raw, nil
+	}
+	if len(packageObject.Object.Raw) > 0 {
+		return nil, fmt.Errorf("object and compressedObject are mutually exclusive")
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(packageObject.CompressedObject))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing object: %w", err)
+	}
+	defer r.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(r, maxDecompressedObjectSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing object: %w", err)
+	}
+	if len(raw) > maxDecompressedObjectSize {
+		return nil, fmt.Errorf(
+			"decompressing object: exceeds %d bytes", maxDecompressedObjectSize)
+	}
+	return raw, nil
+}
+
+// Returns the template with all compressed objects stored uncompressed,
+// so the representation of objects does not influence the template hash.
+func decompressTemplate(
+	template packagesv1alpha1.ObjectSetTemplate,
+) (packagesv1alpha1.ObjectSetTemplate, error) {
+	if !hasCompressedObjects(template.Spec.Phases) {
+		return template, nil
+	}
+
+	template = *template.DeepCopy()
+	for i := range template.Spec.Phases {
+		objects := template.Spec.Phases[i].Objects
+		for j := range objects {
+			if len(objects[j].CompressedObject) == 0 {
+				continue
+			}
+			raw, err := objectSetObjectRaw(&objects[j])
+			if err != nil {
+				return template, err
+			}
+			objects[j] = packagesv1alpha1.ObjectSetObject{}
+			objects[j].Object.Raw = raw
+		}
+	}
+	return template, nil
+}
+
+func hasCompressedObjects(phases []packagesv1alpha1.ObjectPhase) bool {
+	for _, phase := range phases {
+		for _, obj := range phase.Objects {
+			if len(obj.CompressedObject) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
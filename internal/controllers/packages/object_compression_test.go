@@ -0,0 +1,55 @@
+package packages
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestCompressObject(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cm"},
+		"data":       map[string]interface{}{"key": "value"},
+	}}
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	compressed, err := CompressObject(obj)
+	require.NoError(t, err)
+	assert.Empty(t, compressed.Object.Raw)
+	assert.NotEmpty(t, compressed.CompressedObject)
+
+	decoded, err := UnstructuredFromObjectObject(&compressed)
+	require.NoError(t, err)
+	assert.Equal(t, obj, decoded)
+
+	newTemplate := func(object packagesv1alpha1.ObjectSetObject) packagesv1alpha1.ObjectSetTemplate {
+		return packagesv1alpha1.ObjectSetTemplate{
+			Spec: packagesv1alpha1.ObjectSetTemplateSpec{
+				Phases: []packagesv1alpha1.ObjectPhase{{
+					Name:    "test",
+					Objects: []packagesv1alpha1.ObjectSetObject{object},
+				}},
+			},
+		}
+	}
+	plain := packagesv1alpha1.ObjectSetObject{}
+	plain.Object.Raw = raw
+	assert.Equal(t,
+		ComputeHash(newTemplate(plain), nil),
+		ComputeHash(newTemplate(compressed), nil))
+}
+
+func TestUnstructuredFromObjectObject_invalidCompression(t *testing.T) {
+	_, err := UnstructuredFromObjectObject(&packagesv1alpha1.ObjectSetObject{
+		CompressedObject: []byte("not gzip"),
+	})
+	assert.Error(t, err)
+}
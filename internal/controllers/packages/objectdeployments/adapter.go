@@ -13,11 +13,15 @@ type genericObjectDeployment interface {
 	GetConditions() *[]metav1.Condition
 	GetSelector() metav1.LabelSelector
 	GetObjectSetTemplate() packagesv1alpha1.ObjectSetTemplate
+	SetObjectSetTemplate(template packagesv1alpha1.ObjectSetTemplate)
+	GetRollbackTo() *packagesv1alpha1.ObjectDeploymentRollback
+	SetRollbackTo(rollbackTo *packagesv1alpha1.ObjectDeploymentRollback)
 	GetRevisionHistoryLimit() *int
 	SetStatusCollisionCount(*int32)
 	GetStatusCollisionCount() *int32
 	GetStatusTemplateHash() string
 	SetStatusTemplateHash(templateHash string)
+	SetStatusRevision(revision int64)
 }
 
 var (
@@ -79,6 +83,22 @@ func (a *GenericObjectDeployment) SetStatusTemplateHash(templateHash string) {
 	a.Status.TemplateHash = templateHash
 }
 
+func (a *GenericObjectDeployment) SetObjectSetTemplate(template packagesv1alpha1.ObjectSetTemplate) {
+	a.Spec.Template = template
+}
+
+func (a *GenericObjectDeployment) GetRollbackTo() *packagesv1alpha1.ObjectDeploymentRollback {
+	return a.Spec.RollbackTo
+}
+
+func (a *GenericObjectDeployment) SetRollbackTo(rollbackTo *packagesv1alpha1.ObjectDeploymentRollback) {
+	a.Spec.RollbackTo = rollbackTo
+}
+
+func (a *GenericObjectDeployment) SetStatusRevision(revision int64) {
+	a.Status.Revision = revision
+}
+
 func (a *GenericObjectDeployment) GetStatusTemplateHash() string {
 	return a.Status.TemplateHash
 }
@@ -137,6 +157,22 @@ func (a *GenericClusterObjectDeployment) SetStatusTemplateHash(templateHash stri
 	a.Status.TemplateHash = templateHash
 }
 
+func (a *GenericClusterObjectDeployment) SetObjectSetTemplate(template packagesv1alpha1.ObjectSetTemplate) {
+	a.Spec.Template = template
+}
+
+func (a *GenericClusterObjectDeployment) GetRollbackTo() *packagesv1alpha1.ObjectDeploymentRollback {
+	return a.Spec.RollbackTo
+}
+
+func (a *GenericClusterObjectDeployment) SetRollbackTo(rollbackTo *packagesv1alpha1.ObjectDeploymentRollback) {
+	a.Spec.RollbackTo = rollbackTo
+}
+
+func (a *GenericClusterObjectDeployment) SetStatusRevision(revision int64) {
+	a.Status.Revision = revision
+}
+
 func (a *GenericClusterObjectDeployment) GetStatusTemplateHash() string {
 	return a.Status.TemplateHash
 }
@@ -151,6 +187,8 @@ type genericObjectSet interface {
 	GetSpecPausedFor() []packagesv1alpha1.ObjectSetPausedObject
 	GetStatusPausedFor() []packagesv1alpha1.ObjectSetPausedObject
 	SetArchived()
+	IsArchived() bool
+	SetActive()
 }
 
 type GenericObjectSet struct {
@@ -191,17 +229,49 @@ func (a *GenericObjectSet) GetStatusPausedFor() []packagesv1alpha1.ObjectSetPaus
 }
 
 func (a *GenericObjectSet) SetArchived() {
+	if a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateOrphaned {
+		// already archived, without deleting objects
+		return
+	}
 	a.Spec.LifecycleState = packagesv1alpha1.ObjectSetLifecycleStateArchived
 }
 
+// Orphaned ObjectSets are archived as well, but don't delete their objects.
+func (a *GenericObjectSet) IsArchived() bool {
+	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateArchived ||
+		a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateOrphaned
+}
+
+// Reactivates an archived ObjectSet, taking over all of its objects again.
+func (a *GenericObjectSet) SetActive() {
+	a.Spec.LifecycleState = packagesv1alpha1.ObjectSetLifecycleStateActive
+	a.Spec.PausedFor = nil
+}
+
 type GenericClusterObjectSet struct {
 	packagesv1alpha1.ClusterObjectSet
 }
 
 func (a *GenericClusterObjectSet) SetArchived() {
+	if a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateOrphaned {
+		// already archived, without deleting objects
+		return
+	}
 	a.Spec.LifecycleState = packagesv1alpha1.ObjectSetLifecycleStateArchived
 }
 
+// Orphaned ObjectSets are archived as well, but don't delete their objects.
+func (a *GenericClusterObjectSet) IsArchived() bool {
+	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateArchived ||
+		a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateOrphaned
+}
+
+// Reactivates an archived ObjectSet, taking over all of its objects again.
+func (a *GenericClusterObjectSet) SetActive() {
+	a.Spec.LifecycleState = packagesv1alpha1.ObjectSetLifecycleStateActive
+	a.Spec.PausedFor = nil
+}
+
 func (a *GenericClusterObjectSet) GetTemplateSpec() packagesv1alpha1.ObjectSetTemplateSpec {
 	return a.Spec.ObjectSetTemplateSpec
 }
@@ -280,3 +350,32 @@ func (a *GenericClusterObjectSetList) GetItems() []genericObjectSet {
 	}
 	return out
 }
+
+type genericObjectSetSlice interface {
+	ClientObject() client.Object
+	SetObjects(objects []packagesv1alpha1.ObjectSetObject)
+}
+
+type GenericObjectSetSlice struct {
+	packagesv1alpha1.ObjectSetSlice
+}
+
+func (a *GenericObjectSetSlice) ClientObject() client.Object {
+	return &a.ObjectSetSlice
+}
+
+func (a *GenericObjectSetSlice) SetObjects(objects []packagesv1alpha1.ObjectSetObject) {
+	a.Objects = objects
+}
+
+type GenericClusterObjectSetSlice struct {
+	packagesv1alpha1.ClusterObjectSetSlice
+}
+
+func (a *GenericClusterObjectSetSlice) ClientObject() client.Object {
+	return &a.ClusterObjectSetSlice
+}
+
+func (a *GenericClusterObjectSetSlice) SetObjects(objects []packagesv1alpha1.ObjectSetObject) {
+	a.Objects = objects
+}
@@ -3,6 +3,7 @@ package objectdeployments
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -26,6 +27,8 @@ func (r *DeprecationReconciler) Reconcile(
 	var (
 		objectSetsForCleanup      []genericObjectSet
 		objectDeploymentAvailable bool
+		// revision of the newest available ObjectSet.
+		liveRevision int64
 	)
 
 	if currentObjectSet != nil &&
@@ -37,6 +40,7 @@ func (r *DeprecationReconciler) Reconcile(
 		// because we progressed to a newer version.
 		objectSetsForCleanup = outdatedObjectSets
 		objectDeploymentAvailable = true
+		liveRevision = objectSetRevision(currentObjectSet)
 
 		// We are also no longer progressing, because the latest version is available
 		meta.SetStatusCondition(objectDeployment.GetConditions(), metav1.Condition{
@@ -61,6 +65,7 @@ func (r *DeprecationReconciler) Reconcile(
 				// Alright! \o/
 				// we found an older revision still running
 				objectDeploymentAvailable = true
+				liveRevision = objectSetRevision(outdatedObjectSet)
 				continue
 			}
 
@@ -80,6 +85,7 @@ func (r *DeprecationReconciler) Reconcile(
 		})
 	}
 
+	objectDeployment.SetStatusRevision(liveRevision)
 	if objectDeploymentAvailable {
 		if err := r.deleteObjectSetsOverLimit(
 			ctx, objectDeployment, objectSetsForCleanup); err != nil {
@@ -108,6 +114,13 @@ func (r *DeprecationReconciler) Reconcile(
 	return ctrl.Result{}, nil
 }
 
+// Returns the revision of the ObjectSet or 0, if it has no valid revision annotation.
+func objectSetRevision(objectSet genericObjectSet) int64 {
+	revision, _ := strconv.ParseInt(
+		objectSet.ClientObject().GetAnnotations()[objectSetRevisionAnnotation], 10, 64)
+	return revision
+}
+
 func (r *DeprecationReconciler) deleteObjectSetsOverLimit(
 	ctx context.Context, objectDeployment genericObjectDeployment,
 	objectSetsForCleanup []genericObjectSet,
@@ -123,12 +136,16 @@ func (r *DeprecationReconciler) deleteObjectSetsOverLimit(
 	var outdatedObjectSetsDeleted int
 	for _, outdatedObjectSet := range objectSetsForCleanup {
 		if outdatedObjectSetsToDelete > outdatedObjectSetsDeleted {
-			if err := r.client.Delete(
-				ctx, outdatedObjectSet.ClientObject()); err != nil {
-				return fmt.Errorf("delete outdated ObjectSet: %w", err)
-			}
 			outdatedObjectSetsDeleted++
-			continue
+			// ObjectSets are archived before they are deleted,
+			// so pruning revisions does not run pre-delete hooks.
+			if outdatedObjectSet.IsArchived() {
+				if err := r.client.Delete(
+					ctx, outdatedObjectSet.ClientObject()); err != nil {
+					return fmt.Errorf("delete outdated ObjectSet: %w", err)
+				}
+				continue
+			}
 		}
 
 		// Archive everything that is not fit for deletion
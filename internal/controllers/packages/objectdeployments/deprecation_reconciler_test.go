@@ -0,0 +1,47 @@
+package objectdeployments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/testutil"
+)
+
+func TestDeprecationReconciler_deleteObjectSetsOverLimit(t *testing.T) {
+	c := testutil.NewClient()
+	c.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	c.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	r := &DeprecationReconciler{client: c}
+
+	newObjectSet := func(name string, state packagesv1alpha1.ObjectSetLifecycleState) *GenericObjectSet {
+		objectSet := &GenericObjectSet{}
+		objectSet.Name = name
+		objectSet.Spec.LifecycleState = state
+		return objectSet
+	}
+	archived := newObjectSet("archived", packagesv1alpha1.ObjectSetLifecycleStateArchived)
+	active := newObjectSet("active", packagesv1alpha1.ObjectSetLifecycleStateActive)
+	kept := newObjectSet("kept", packagesv1alpha1.ObjectSetLifecycleStateActive)
+
+	deploy := &GenericObjectDeployment{}
+	deploy.Spec.RevisionHistoryLimit = pointer.Int(1)
+	err := r.deleteObjectSetsOverLimit(
+		context.Background(), deploy, []genericObjectSet{archived, active, kept})
+	require.NoError(t, err)
+
+	// Only archived ObjectSets are deleted, so pruning does not run pre-delete hooks.
+	c.AssertCalled(t, "Delete", mock.Anything, &archived.ObjectSet, mock.Anything)
+	c.AssertNumberOfCalls(t, "Delete", 1)
+
+	// ObjectSets over the limit are archived first and deleted on the next reconcile.
+	c.AssertCalled(t, "Update", mock.Anything, &active.ObjectSet, mock.Anything)
+	c.AssertCalled(t, "Update", mock.Anything, &kept.ObjectSet, mock.Anything)
+	assert.Equal(t, packagesv1alpha1.ObjectSetLifecycleStateArchived, active.Spec.LifecycleState)
+	assert.Equal(t, packagesv1alpha1.ObjectSetLifecycleStateArchived, kept.Spec.LifecycleState)
+}
@@ -2,14 +2,17 @@ package objectdeployments
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -19,9 +22,11 @@ import (
 // creates a new ObjectSet, if required.
 // handles hash collisions if they occur.
 type NewRevisionReconciler struct {
-	client       client.Client
-	scheme       *runtime.Scheme
-	newObjectSet func() genericObjectSet
+	client            client.Client
+	scheme            *runtime.Scheme
+	recorder          record.EventRecorder
+	newObjectSet      func() genericObjectSet
+	newObjectSetSlice func() genericObjectSetSlice
 }
 
 func (r *NewRevisionReconciler) Reconcile(
@@ -31,8 +36,16 @@ func (r *NewRevisionReconciler) Reconcile(
 ) (ctrl.Result, error) {
 	if currentObjectSet != nil {
 		// there is a current ObjectSet,
-		// no need to create a new one
-		return ctrl.Result{}, nil
+		// no need to create a new one,
+		// but creating or handing over its slices might have failed before.
+		if currentObjectSet.IsArchived() {
+			// the template was rolled back to an archived revision.
+			if err := r.reactivate(ctx, objectDeployment, currentObjectSet, outdatedObjectSets); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		obj := currentObjectSet.ClientObject()
+		return ctrl.Result{}, r.ensureSlices(ctx, objectDeployment, obj.GetName(), obj)
 	}
 
 	log := controllers.LoggerFromContext(ctx)
@@ -51,12 +64,25 @@ func (r *NewRevisionReconciler) Reconcile(
 			fmt.Errorf("creating new ObjectSet: %w", err)
 	}
 
+	// slices need to exist before the ObjectSet referencing them.
+	if err := r.ensureSlices(
+		ctx, objectDeployment, newObjectSet.ClientObject().GetName(),
+		objectDeployment.ClientObject()); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	err = r.client.Create(ctx, newObjectSet.ClientObject())
 	if err != nil && !errors.IsAlreadyExists(err) {
 		return ctrl.Result{}, fmt.Errorf("creating new ObjectSet: %w", err)
 	}
 	if err == nil {
-		return ctrl.Result{}, nil
+		revision := newObjectSet.ClientObject().GetAnnotations()[objectSetRevisionAnnotation]
+		r.recorder.Eventf(objectDeployment.ClientObject(), corev1.EventTypeNormal, "NewRevision",
+			"Created ObjectSet %s for revision %s.", newObjectSet.ClientObject().GetName(), revision)
+		r.recorder.Eventf(newObjectSet.ClientObject(), corev1.EventTypeNormal, "NewRevision",
+			"Created for revision %s of %s.", revision, objectDeployment.ClientObject().GetName())
+		obj := newObjectSet.ClientObject()
+		return ctrl.Result{}, r.ensureSlices(ctx, objectDeployment, obj.GetName(), obj)
 	}
 
 	// errors.IsAlreadyExists(err)
@@ -94,12 +120,47 @@ func (r *NewRevisionReconciler) Reconcile(
 		*cc++
 		objectDeployment.SetStatusCollisionCount(cc)
 
+		message := fmt.Sprintf("ObjectSet %s collides with the new revision, retrying with collision count %d.",
+			conflictingObjectSet.ClientObject().GetName(), *cc)
+		r.recorder.Event(objectDeployment.ClientObject(), corev1.EventTypeWarning, "HashCollision", message)
+		r.recorder.Event(conflictingObjectSet.ClientObject(), corev1.EventTypeWarning, "HashCollision", message)
+
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// Reactivates an archived ObjectSet as the latest revision,
+// to keep revision numbers increasing.
+func (r *NewRevisionReconciler) reactivate(
+	ctx context.Context, objectDeployment genericObjectDeployment,
+	objectSet genericObjectSet, outdatedObjectSets []genericObjectSet,
+) error {
+	latestRevision, err := latestRevision(outdatedObjectSets)
+	if err != nil {
+		return fmt.Errorf("calculating latest revision: %w", err)
+	}
+
+	obj := objectSet.ClientObject()
+	annotations := obj.GetAnnotations()
+	previousRevision := annotations[objectSetRevisionAnnotation]
+	if current, _ := strconv.Atoi(previousRevision); current <= latestRevision {
+		annotations[objectSetRevisionAnnotation] = strconv.Itoa(latestRevision + 1)
+	}
+	objectSet.SetActive()
+	if err := r.client.Update(ctx, obj); err != nil {
+		return fmt.Errorf("reactivating ObjectSet: %w", err)
+	}
+
+	revision := annotations[objectSetRevisionAnnotation]
+	r.recorder.Eventf(objectDeployment.ClientObject(), corev1.EventTypeNormal, "Reactivated",
+		"Reactivated ObjectSet %s of revision %s as revision %s.", obj.GetName(), previousRevision, revision)
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, "Reactivated",
+		"Reactivated as revision %s of %s.", revision, objectDeployment.ClientObject().GetName())
+	return nil
+}
+
 func (r *NewRevisionReconciler) newObjectSetFromDeployment(
 	objectDeployment genericObjectDeployment,
 	latestRevision int,
@@ -113,14 +174,26 @@ func (r *NewRevisionReconciler) newObjectSetFromDeployment(
 	new.SetNamespace(deploy.GetNamespace())
 	new.SetAnnotations(deploy.GetAnnotations())
 	new.SetLabels(objectDeployment.GetObjectSetTemplate().Metadata.Labels)
-	newObjectSet.SetTemplateSpec(
-		objectDeployment.GetObjectSetTemplate().Spec)
+
+	// Objects of large templates are stored in slices,
+	// created ahead of the ObjectSet referencing them.
+	templateSpec, _, err := splitTemplate(
+		objectDeployment.GetObjectSetTemplate().Spec, new.GetName())
+	if err != nil {
+		return nil, fmt.Errorf("splitting template: %w", err)
+	}
+	newObjectSet.SetTemplateSpec(templateSpec)
 
 	if new.GetAnnotations() == nil {
 		new.SetAnnotations(map[string]string{})
 	}
 	new.GetAnnotations()[objectSetHashAnnotation] = templateHash
 	new.GetAnnotations()[objectSetRevisionAnnotation] = strconv.Itoa(latestRevision + 1)
+	templateLabels, err := json.Marshal(objectDeployment.GetObjectSetTemplate().Metadata.Labels)
+	if err != nil {
+		return nil, err
+	}
+	new.GetAnnotations()[objectSetTemplateLabelsAnnotation] = string(templateLabels)
 	if err := controllerutil.SetControllerReference(
 		deploy, new, r.scheme); err != nil {
 		return nil, err
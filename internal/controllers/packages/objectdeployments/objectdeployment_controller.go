@@ -8,16 +8,22 @@ import (
 	"github.com/go-logr/logr"
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers"
+	"github.com/thetechnick/package-operator/internal/metrics"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	objectSetHashAnnotation     = "packages.thetechnick.ninja/hash"
-	objectSetRevisionAnnotation = "packages.thetechnick.ninja/revision"
+	objectSetRevisionAnnotation = packagesv1alpha1.ObjectSetRevisionAnnotation
+	// JSON of the template labels the ObjectSet was created from,
+	// as labels may be added to the ObjectSet later on.
+	objectSetTemplateLabelsAnnotation = "packages.thetechnick.ninja/template-labels"
 )
 
 // Generic reconciler for both ObjectDeployment and ClusterObjectDeployment objects.
@@ -28,6 +34,7 @@ type GenericObjectDeploymentController struct {
 	client     client.Client
 	log        logr.Logger
 	scheme     *runtime.Scheme
+	recorder   record.EventRecorder
 	reconciler []reconciler
 }
 
@@ -37,21 +44,23 @@ type reconciler interface {
 
 func NewObjectDeploymentController(
 	c client.Client, log logr.Logger, scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 ) *GenericObjectDeploymentController {
 	return NewGenericObjectDeploymentController(
 		packagesv1alpha1.GroupVersion.WithKind("ObjectDeployment"),
 		packagesv1alpha1.GroupVersion.WithKind("ObjectSet"),
-		c, log, scheme,
+		c, log, scheme, recorder,
 	)
 }
 
 func NewClusterObjectDeploymentController(
 	c client.Client, log logr.Logger, scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 ) *GenericObjectDeploymentController {
 	return NewGenericObjectDeploymentController(
 		packagesv1alpha1.GroupVersion.WithKind("ClusterObjectDeployment"),
 		packagesv1alpha1.GroupVersion.WithKind("ClusterObjectSet"),
-		c, log, scheme,
+		c, log, scheme, recorder,
 	)
 }
 
@@ -59,25 +68,34 @@ func NewGenericObjectDeploymentController(
 	gvk schema.GroupVersionKind,
 	childGVK schema.GroupVersionKind,
 	c client.Client, log logr.Logger, scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 ) *GenericObjectDeploymentController {
 	controller := &GenericObjectDeploymentController{
 		gvk:      gvk,
 		childGVK: childGVK,
 
-		client: c,
-		log:    log,
-		scheme: scheme,
+		client:   c,
+		log:      log,
+		scheme:   scheme,
+		recorder: recorder,
 	}
 	controller.reconciler = []reconciler{
+		&RollbackReconciler{
+			client:                      c,
+			recorder:                    recorder,
+			listObjectSetsForDeployment: controller.listObjectSetsByRevision,
+		},
 		&HashReconciler{},
 		&EnsurePauseReconciler{
 			client:                      c,
 			listObjectSetsForDeployment: controller.listObjectSetsByRevision,
 			reconcilers: []objectSetReconciler{
 				&NewRevisionReconciler{
-					client:       c,
-					scheme:       scheme,
-					newObjectSet: controller.newOperandChild,
+					client:            c,
+					scheme:            scheme,
+					recorder:          recorder,
+					newObjectSet:      controller.newOperandChild,
+					newObjectSetSlice: controller.newOperandChildSlice,
 				},
 				&DeprecationReconciler{
 					client: c,
@@ -118,6 +136,23 @@ func (c *GenericObjectDeploymentController) newOperandChild() genericObjectSet {
 	panic("unsupported gvk")
 }
 
+func (c *GenericObjectDeploymentController) newOperandChildSlice() genericObjectSetSlice {
+	childSliceGVK := c.childGVK.GroupVersion().
+		WithKind(c.childGVK.Kind + "Slice")
+	obj, err := c.scheme.New(childSliceGVK)
+	if err != nil {
+		panic(err)
+	}
+
+	switch o := obj.(type) {
+	case *packagesv1alpha1.ObjectSetSlice:
+		return &GenericObjectSetSlice{ObjectSetSlice: *o}
+	case *packagesv1alpha1.ClusterObjectSetSlice:
+		return &GenericClusterObjectSetSlice{ClusterObjectSetSlice: *o}
+	}
+	panic("unsupported gvk")
+}
+
 func (c *GenericObjectDeploymentController) newOperandChildList() genericObjectSetList {
 	childListGVK := c.childGVK.GroupVersion().
 		WithKind(c.childGVK.Kind + "List")
@@ -153,6 +188,10 @@ func (c *GenericObjectDeploymentController) Reconcile(
 	objectDeployment := c.newOperand()
 	if err := c.client.Get(
 		ctx, req.NamespacedName, objectDeployment.ClientObject()); err != nil {
+		if errors.IsNotFound(err) {
+			metrics.ObjectDeploymentRevisions.DeleteLabelValues(
+				c.gvk.Kind, req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -196,6 +235,10 @@ func (c *GenericObjectDeploymentController) listObjectSetsByRevision(
 	}
 
 	items := objectSetList.GetItems()
+	metrics.ObjectDeploymentRevisions.WithLabelValues(
+		c.gvk.Kind, objectDeployment.ClientObject().GetNamespace(),
+		objectDeployment.ClientObject().GetName(),
+	).Set(float64(len(items)))
 
 	// Ensure everything is sorted by revision.
 	sort.Sort(objectSetsByRevision(items))
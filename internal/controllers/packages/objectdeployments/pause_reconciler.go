@@ -39,7 +39,9 @@ func (r *EnsurePauseReconciler) Reconcile(
 	log := controllers.LoggerFromContext(ctx)
 
 	pausedObjects, err := pausedObjectsFromPhases(
-		objectDeployment.GetObjectSetTemplate().Spec.Phases)
+		objectDeployment.GetObjectSetTemplate().Spec.Phases,
+		objectDeployment.ClientObject().GetNamespace(),
+		r.client.RESTMapper())
 	if err != nil {
 		return ctrl.Result{},
 			fmt.Errorf("getting paused objects from ObjectSet: %w", err)
@@ -0,0 +1,118 @@
+package objectdeployments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Restores the template of a previous revision, when requested via spec.rollbackTo.
+// The restored template is rolled out like any other template change,
+// reusing the ObjectSet of the revision, if the template hash still matches.
+type RollbackReconciler struct {
+	client                      client.Client
+	recorder                    record.EventRecorder
+	listObjectSetsForDeployment listObjectSetsForDeploymentFn
+}
+
+func (r *RollbackReconciler) Reconcile(
+	ctx context.Context, objectDeployment genericObjectDeployment,
+) (ctrl.Result, error) {
+	rollbackTo := objectDeployment.GetRollbackTo()
+	if rollbackTo == nil {
+		return ctrl.Result{}, nil
+	}
+
+	log := controllers.LoggerFromContext(ctx)
+	deploy := objectDeployment.ClientObject()
+
+	objectSets, err := r.listObjectSetsForDeployment(ctx, objectDeployment)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("list ObjectSets: %w", err)
+	}
+	revision := strconv.FormatInt(rollbackTo.Revision, 10)
+	var objectSet genericObjectSet
+	for i := range objectSets {
+		if objectSets[i].ClientObject().GetAnnotations()[objectSetRevisionAnnotation] == revision {
+			objectSet = objectSets[i]
+			break
+		}
+	}
+
+	objectDeployment.SetRollbackTo(nil)
+	if objectSet == nil {
+		if err := r.client.Update(ctx, deploy); err != nil {
+			return ctrl.Result{}, fmt.Errorf("clearing rollbackTo: %w", err)
+		}
+		r.recorder.Eventf(deploy, corev1.EventTypeWarning, "RollbackRevisionNotFound",
+			"Unable to roll back to revision %s, no ObjectSet found.", revision)
+		return ctrl.Result{}, nil
+	}
+
+	template, err := templateFromObjectSet(
+		ctx, r.client, objectDeployment.GetObjectSetTemplate(), objectSet)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("restoring template of revision %s: %w", revision, err)
+	}
+	objectDeployment.SetObjectSetTemplate(template)
+	if err := r.client.Update(ctx, deploy); err != nil {
+		return ctrl.Result{}, fmt.Errorf("restoring template of revision %s: %w", revision, err)
+	}
+
+	log.Info("rolled back", "revision", revision)
+	r.recorder.Eventf(deploy, corev1.EventTypeNormal, "RolledBack",
+		"Rolled back to the template of revision %s from ObjectSet %s.",
+		revision, objectSet.ClientObject().GetName())
+	return ctrl.Result{}, nil
+}
+
+// Returns the given template with labels and spec restored from the ObjectSet.
+// Objects stored in slices are inlined again at their original position,
+// so the restored template hashes to the revision of the ObjectSet.
+func templateFromObjectSet(
+	ctx context.Context, c client.Reader,
+	template packagesv1alpha1.ObjectSetTemplate, objectSet genericObjectSet,
+) (packagesv1alpha1.ObjectSetTemplate, error) {
+	spec := objectSet.GetTemplateSpec()
+	phases := make([]packagesv1alpha1.ObjectPhase, len(spec.Phases))
+	for i, phase := range spec.Phases {
+		resolved, err := packages.ResolveSlices(ctx, c, objectSet.ClientObject(), phase)
+		if err != nil {
+			return template, err
+		}
+		phases[i] = resolved
+	}
+	spec.Phases = phases
+
+	labels, err := templateLabelsFromObjectSet(objectSet)
+	if err != nil {
+		return template, err
+	}
+	template.Metadata.Labels = labels
+	template.Spec = spec
+	return template, nil
+}
+
+// ObjectSets created before template labels were recorded
+// fall back to the labels of the ObjectSet.
+func templateLabelsFromObjectSet(objectSet genericObjectSet) (map[string]string, error) {
+	obj := objectSet.ClientObject()
+	annotation, ok := obj.GetAnnotations()[objectSetTemplateLabelsAnnotation]
+	if !ok {
+		return obj.GetLabels(), nil
+	}
+	var labels map[string]string
+	if err := json.Unmarshal([]byte(annotation), &labels); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", objectSetTemplateLabelsAnnotation, err)
+	}
+	return labels, nil
+}
@@ -0,0 +1,123 @@
+package objectdeployments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
+	"github.com/thetechnick/package-operator/internal/testutil"
+)
+
+func TestRollbackReconciler(t *testing.T) {
+	objectSet := &GenericObjectSet{}
+	objectSet.Name = "test-1"
+	objectSet.Labels = map[string]string{"version": "1"}
+	objectSet.Annotations = map[string]string{objectSetRevisionAnnotation: "1"}
+	objectSet.Spec.Phases = []packagesv1alpha1.ObjectPhase{{
+		Name:    "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{configMapObject("cm", 10)},
+	}}
+	list := func(context.Context, genericObjectDeployment) ([]genericObjectSet, error) {
+		return []genericObjectSet{objectSet}, nil
+	}
+
+	tests := []struct {
+		name     string
+		revision int64
+		event    string
+	}{
+		{name: "restores template", revision: 1, event: "Normal RolledBack"},
+		{name: "revision not found", revision: 2, event: "Warning RollbackRevisionNotFound"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := testutil.NewClient()
+			c.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			recorder := record.NewFakeRecorder(1)
+			r := &RollbackReconciler{
+				client:                      c,
+				recorder:                    recorder,
+				listObjectSetsForDeployment: list,
+			}
+
+			deploy := &GenericObjectDeployment{}
+			deploy.Spec.Template.Metadata.Labels = map[string]string{"version": "2"}
+			deploy.Spec.RollbackTo = &packagesv1alpha1.ObjectDeploymentRollback{Revision: test.revision}
+
+			res, err := r.Reconcile(context.Background(), deploy)
+			require.NoError(t, err)
+			assert.True(t, res.IsZero())
+
+			assert.Nil(t, deploy.Spec.RollbackTo)
+			c.AssertCalled(t, "Update", mock.Anything, &deploy.ObjectDeployment, mock.Anything)
+			if assert.Len(t, recorder.Events, 1) {
+				assert.Contains(t, <-recorder.Events, test.event)
+			}
+
+			if test.revision == 1 {
+				assert.Equal(t, objectSet.Labels, deploy.Spec.Template.Metadata.Labels)
+				assert.Equal(t, objectSet.Spec.Phases, deploy.Spec.Template.Spec.Phases)
+			} else {
+				assert.Equal(t, "2", deploy.Spec.Template.Metadata.Labels["version"])
+			}
+		})
+	}
+}
+
+func TestTemplateFromObjectSet_slices(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &NewRevisionReconciler{
+		client: c,
+		scheme: scheme,
+		newObjectSet: func() genericObjectSet {
+			return &GenericObjectSet{}
+		},
+		newObjectSetSlice: func() genericObjectSetSlice {
+			return &GenericObjectSetSlice{}
+		},
+	}
+	ctx := context.Background()
+
+	hook := configMapObject("hook", 10)
+	hook.Object.Raw = []byte(`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"migrate",` +
+		`"annotations":{"packages.thetechnick.ninja/hook":"pre-upgrade"}}}`)
+	deploy := &GenericObjectDeployment{}
+	deploy.Name = "deploy"
+	deploy.Namespace = "test"
+	deploy.UID = "deploy-uid"
+	deploy.Spec.Template.Metadata.Labels = map[string]string{"version": "1"}
+	deploy.Spec.Template.Spec.Phases = []packagesv1alpha1.ObjectPhase{{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			configMapObject("a", 300*1024),
+			hook,
+			configMapObject("b", 300*1024),
+			configMapObject("c", 100*1024),
+		},
+	}}
+	deploy.Status.TemplateHash = packages.ComputeHash(deploy.Spec.Template, nil)
+
+	objectSet, err := r.newObjectSetFromDeployment(deploy, 0)
+	require.NoError(t, err)
+	obj := objectSet.ClientObject()
+	obj.SetUID("os-uid")
+	require.NoError(t, r.ensureSlices(ctx, deploy, obj.GetName(), obj))
+	require.NotEmpty(t, objectSet.GetPhases()[0].Slices)
+	// labels added to the ObjectSet are not part of the template.
+	obj.SetLabels(map[string]string{"version": "1", "other": "label"})
+
+	template, err := templateFromObjectSet(ctx, c, packagesv1alpha1.ObjectSetTemplate{}, objectSet)
+	require.NoError(t, err)
+	assert.Equal(t, deploy.Spec.Template, template)
+	assert.Equal(t, deploy.Status.TemplateHash, packages.ComputeHash(template, nil))
+}
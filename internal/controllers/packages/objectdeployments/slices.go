@@ -0,0 +1,190 @@
+package objectdeployments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
+)
+
+const (
+	// ObjectSet templates larger than this are split into slices,
+	// to stay clear of the etcd object size limit of 1.5MiB.
+	maxInlineTemplateSize = 512 * 1024
+	// Maximum size of objects stored in a single slice.
+	// Larger objects are stored in a slice of their own.
+	maxSliceSize = 512 * 1024
+)
+
+// Objects of a phase moved into a slice.
+type templateSlice struct {
+	name    string
+	objects []packagesv1alpha1.ObjectSetObject
+	// position of each object in the phase.
+	indices []int
+}
+
+// Moves the objects of oversized templates into slices named after the ObjectSet.
+// Splitting is deterministic, so slices can be recreated from the template.
+// Hooks stay inline, because they are only run from the ObjectSet itself.
+func splitTemplate(
+	templateSpec packagesv1alpha1.ObjectSetTemplateSpec, objectSetName string,
+) (packagesv1alpha1.ObjectSetTemplateSpec, []templateSlice, error) {
+	templateSize, err := jsonSize(templateSpec)
+	if err != nil {
+		return templateSpec, nil, err
+	}
+	if templateSize <= maxInlineTemplateSize {
+		return templateSpec, nil, nil
+	}
+
+	var slices []templateSlice
+	phases := make([]packagesv1alpha1.ObjectPhase, len(templateSpec.Phases))
+	for i, phase := range templateSpec.Phases {
+		var (
+			inline      []packagesv1alpha1.ObjectSetObject
+			phaseSlices []templateSlice
+			sliceSize   int
+		)
+		for j := range phase.Objects {
+			obj, err := packages.UnstructuredFromObjectObject(&phase.Objects[j])
+			if err != nil {
+				return templateSpec, nil, err
+			}
+			if _, isHook := obj.GetAnnotations()[packagesv1alpha1.HookAnnotation]; isHook {
+				inline = append(inline, phase.Objects[j])
+				continue
+			}
+
+			objSize, err := jsonSize(phase.Objects[j])
+			if err != nil {
+				return templateSpec, nil, err
+			}
+			if len(phaseSlices) == 0 || sliceSize+objSize > maxSliceSize {
+				phaseSlices = append(phaseSlices, templateSlice{
+					name: fmt.Sprintf("%s-%d-%d", objectSetName, i, len(phaseSlices)),
+				})
+				sliceSize = 0
+			}
+			last := &phaseSlices[len(phaseSlices)-1]
+			last.objects = append(last.objects, phase.Objects[j])
+			last.indices = append(last.indices, j)
+			sliceSize += objSize
+		}
+
+		phase.Objects = inline
+		phase.Slices = append([]string{}, phase.Slices...)
+		for _, slice := range phaseSlices {
+			phase.Slices = append(phase.Slices, slice.name)
+		}
+		phases[i] = phase
+		slices = append(slices, phaseSlices...)
+	}
+	templateSpec.Phases = phases
+	return templateSpec, slices, nil
+}
+
+// Creates the slices of the ObjectSet with the given name, controlled by owner.
+// Slices are created controlled by the ObjectDeployment before the ObjectSet,
+// so the ObjectSet never references missing slices,
+// and are handed over to the ObjectSet, once it exists.
+func (r *NewRevisionReconciler) ensureSlices(
+	ctx context.Context, objectDeployment genericObjectDeployment,
+	objectSetName string, owner client.Object,
+) error {
+	_, slices, err := splitTemplate(
+		objectDeployment.GetObjectSetTemplate().Spec, objectSetName)
+	if err != nil {
+		return fmt.Errorf("splitting template: %w", err)
+	}
+
+	for _, s := range slices {
+		slice := r.newObjectSetSlice()
+		obj := slice.ClientObject()
+		obj.SetName(s.name)
+		obj.SetNamespace(objectDeployment.ClientObject().GetNamespace())
+
+		err := r.client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if err == nil {
+			if err := r.handoverSlice(ctx, objectDeployment, owner, obj); err != nil {
+				return err
+			}
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("getting slice: %w", err)
+		}
+
+		slice.SetObjects(s.objects)
+		indices, err := json.Marshal(s.indices)
+		if err != nil {
+			return err
+		}
+		// objects are restored to their position in the phase, when the slice is resolved.
+		obj.SetAnnotations(map[string]string{
+			packages.SliceObjectIndicesAnnotation: string(indices),
+		})
+		if err := r.setSliceController(objectDeployment, owner, obj); err != nil {
+			return err
+		}
+		if err := r.client.Create(ctx, obj); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("creating slice: %w", err)
+		}
+	}
+	return nil
+}
+
+// Hands a slice created ahead of the ObjectSet over to the ObjectSet.
+// Slices controlled by anyone else, e.g. a colliding ObjectSet, are left alone.
+func (r *NewRevisionReconciler) handoverSlice(
+	ctx context.Context, objectDeployment genericObjectDeployment,
+	owner, slice client.Object,
+) error {
+	deploy := objectDeployment.ClientObject()
+	controller := metav1.GetControllerOf(slice)
+	if controller == nil || controller.UID != deploy.GetUID() ||
+		owner.GetUID() == deploy.GetUID() {
+		return nil
+	}
+
+	var ownerRefs []metav1.OwnerReference
+	for _, ownerRef := range slice.GetOwnerReferences() {
+		if ownerRef.UID != deploy.GetUID() {
+			ownerRefs = append(ownerRefs, ownerRef)
+		}
+	}
+	slice.SetOwnerReferences(ownerRefs)
+	if err := r.setSliceController(objectDeployment, owner, slice); err != nil {
+		return err
+	}
+	if err := r.client.Update(ctx, slice); err != nil {
+		return fmt.Errorf("handing over slice: %w", err)
+	}
+	return nil
+}
+
+// Slices controlled by an ObjectSet are kept until the ObjectSet is torn down.
+// Slices of the ObjectDeployment are not, as they might never be handed over.
+func (r *NewRevisionReconciler) setSliceController(
+	objectDeployment genericObjectDeployment, owner, slice client.Object,
+) error {
+	if owner.GetUID() != objectDeployment.ClientObject().GetUID() {
+		controllerutil.AddFinalizer(slice, packages.SliceFinalizer)
+	}
+	return controllerutil.SetControllerReference(owner, slice, r.scheme)
+}
+
+func jsonSize(v interface{}) (int, error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+	return len(j), nil
+}
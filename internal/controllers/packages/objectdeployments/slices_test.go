@@ -0,0 +1,116 @@
+package objectdeployments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
+)
+
+func configMapObject(name string, size int) packagesv1alpha1.ObjectSetObject {
+	return packagesv1alpha1.ObjectSetObject{
+		Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(
+			`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":%q},"data":{"x":%q}}`,
+			name, strings.Repeat("x", size)))},
+	}
+}
+
+func TestSplitTemplate(t *testing.T) {
+	small := packagesv1alpha1.ObjectSetTemplateSpec{
+		Phases: []packagesv1alpha1.ObjectPhase{{
+			Name:    "test",
+			Objects: []packagesv1alpha1.ObjectSetObject{configMapObject("cm", 10)},
+		}},
+	}
+	spec, slices, err := splitTemplate(small, "os")
+	require.NoError(t, err)
+	assert.Equal(t, small, spec)
+	assert.Empty(t, slices)
+
+	hook := packagesv1alpha1.ObjectSetObject{
+		Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"migrate",` +
+			`"annotations":{"packages.thetechnick.ninja/hook":"pre-upgrade"}}}`)},
+	}
+	large := packagesv1alpha1.ObjectSetTemplateSpec{
+		Phases: []packagesv1alpha1.ObjectPhase{{
+			Name: "test",
+			Objects: []packagesv1alpha1.ObjectSetObject{
+				configMapObject("a", 300*1024),
+				hook,
+				configMapObject("b", 300*1024),
+				configMapObject("c", 100*1024),
+			},
+		}},
+	}
+	spec, slices, err = splitTemplate(large, "os")
+	require.NoError(t, err)
+
+	assert.Equal(t, []packagesv1alpha1.ObjectSetObject{hook}, spec.Phases[0].Objects)
+	assert.Equal(t, []string{"os-0-0", "os-0-1"}, spec.Phases[0].Slices)
+	if assert.Len(t, slices, 2) {
+		assert.Equal(t, large.Phases[0].Objects[:1], slices[0].objects)
+		assert.Equal(t, []int{0}, slices[0].indices)
+		assert.Equal(t, large.Phases[0].Objects[2:], slices[1].objects)
+		assert.Equal(t, []int{2, 3}, slices[1].indices)
+	}
+}
+
+func TestNewRevisionReconciler_ensureSlices(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &NewRevisionReconciler{
+		client: c,
+		scheme: scheme,
+		newObjectSetSlice: func() genericObjectSetSlice {
+			return &GenericObjectSetSlice{}
+		},
+	}
+	ctx := context.Background()
+
+	deploy := &GenericObjectDeployment{}
+	deploy.Name = "deploy"
+	deploy.Namespace = "test"
+	deploy.UID = "deploy-uid"
+	deploy.Spec.Template.Spec.Phases = []packagesv1alpha1.ObjectPhase{{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			configMapObject("a", 300*1024),
+			configMapObject("b", 300*1024),
+		},
+	}}
+	objectSet := &packagesv1alpha1.ObjectSet{}
+	objectSet.Name = "deploy-1234"
+	objectSet.Namespace = "test"
+	objectSet.UID = "os-uid"
+
+	// Slices are created ahead of the ObjectSet.
+	require.NoError(t, r.ensureSlices(ctx, deploy, objectSet.Name, &deploy.ObjectDeployment))
+	slice := &packagesv1alpha1.ObjectSetSlice{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: "deploy-1234-0-0", Namespace: "test"}, slice))
+	if controller := metav1.GetControllerOf(slice); assert.NotNil(t, controller) {
+		assert.Equal(t, deploy.UID, controller.UID)
+	}
+	assert.Empty(t, slice.Finalizers)
+
+	// and handed over once the ObjectSet exists.
+	require.NoError(t, r.ensureSlices(ctx, deploy, objectSet.Name, objectSet))
+	for _, name := range []string{"deploy-1234-0-0", "deploy-1234-0-1"} {
+		slice := &packagesv1alpha1.ObjectSetSlice{}
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: name, Namespace: "test"}, slice))
+		if assert.Len(t, slice.OwnerReferences, 1) {
+			assert.Equal(t, objectSet.UID, slice.OwnerReferences[0].UID)
+		}
+		assert.Equal(t, []string{packages.SliceFinalizer}, slice.Finalizers)
+	}
+}
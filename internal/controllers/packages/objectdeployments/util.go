@@ -3,24 +3,46 @@ package objectdeployments
 import (
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"sigs.k8s.io/yaml"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
 )
 
-func pausedObjectsFromPhases(phases []packagesv1alpha1.ObjectPhase) (
-	[]packagesv1alpha1.ObjectSetPausedObject, error) {
+// Returns the objects of the given phases to pause in outdated ObjectSets.
+// Namespaced objects without namespace are placed into the given default namespace,
+// so same-named objects in other namespaces are not paused.
+// Cluster-scoped objects and objects of unknown kinds are paused without namespace,
+// as their live objects don't have one.
+func pausedObjectsFromPhases(
+	phases []packagesv1alpha1.ObjectPhase, defaultNamespace string,
+	restMapper meta.RESTMapper,
+) ([]packagesv1alpha1.ObjectSetPausedObject, error) {
 	var pausedObject []packagesv1alpha1.ObjectSetPausedObject
 	for _, phase := range phases {
 		for _, phaseObject := range phase.Objects {
-			obj := &unstructured.Unstructured{}
-			if err := yaml.Unmarshal(phaseObject.Object.Raw, obj); err != nil {
-				return nil, fmt.Errorf("converting RawExtension into unstructured: %w", err)
+			obj, err := packages.UnstructuredFromObjectObject(&phaseObject)
+			if err != nil {
+				return nil, err
+			}
+			gvk := obj.GroupVersionKind()
+
+			namespace := obj.GetNamespace()
+			if len(namespace) == 0 {
+				mapping, err := restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+				switch {
+				case meta.IsNoMatchError(err):
+				case err != nil:
+					return nil, fmt.Errorf("getting scope of %s: %w", gvk, err)
+				case mapping.Scope.Name() == meta.RESTScopeNameNamespace:
+					namespace = defaultNamespace
+				}
 			}
 			pausedObject = append(pausedObject, packagesv1alpha1.ObjectSetPausedObject{
-				Group: obj.GroupVersionKind().Group,
-				Kind:  obj.GetKind(),
-				Name:  obj.GetName(),
+				Group:     gvk.Group,
+				Kind:      obj.GetKind(),
+				Name:      obj.GetName(),
+				Namespace: namespace,
 			})
 		}
 	}
@@ -0,0 +1,42 @@
+package objectdeployments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestPausedObjectsFromPhases(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{
+		Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole",
+	}, meta.RESTScopeRoot)
+
+	phases := []packagesv1alpha1.ObjectPhase{{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm","namespace":"other"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(
+				`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"role"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.com/v1","kind":"Unknown","metadata":{"name":"x"}}`)}},
+		},
+	}}
+
+	pausedObjects, err := pausedObjectsFromPhases(phases, "test", restMapper)
+	require.NoError(t, err)
+	assert.Equal(t, []packagesv1alpha1.ObjectSetPausedObject{
+		{Kind: "ConfigMap", Name: "cm", Namespace: "test"},
+		{Kind: "ConfigMap", Name: "cm", Namespace: "other"},
+		{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "role"},
+		{Group: "example.com", Kind: "Unknown", Name: "x"},
+	}, pausedObjects)
+}
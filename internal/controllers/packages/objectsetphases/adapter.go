@@ -14,9 +14,17 @@ type genericObjectSetPhase interface {
 	SetPhase(phase packagesv1alpha1.ObjectPhase)
 	GetPausedFor() []packagesv1alpha1.ObjectSetPausedObject
 	SetStatusPausedFor(pausedFor []packagesv1alpha1.ObjectSetPausedObject)
+	GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus
+	SetStatusPhases(phases []packagesv1alpha1.ObjectPhaseStatus)
 	GetReadinessProbes() []packagesv1alpha1.ObjectSetProbe
+	GetApplyMode() packagesv1alpha1.ObjectSetApplyMode
+	GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy
+	GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy
+	GetStatusDrift() *packagesv1alpha1.ObjectSetDriftStatus
+	SetStatusDrift(drift *packagesv1alpha1.ObjectSetDriftStatus)
 	GetPhase() packagesv1alpha1.ObjectPhase
 	IsPaused() bool
+	IsOrphaned() bool
 	GetClass() string
 	IsObjectPaused(obj client.Object) bool
 }
@@ -51,10 +59,38 @@ func (a *GenericObjectSetPhase) SetStatusPausedFor(
 	a.Status.PausedFor = pausedFor
 }
 
+func (a *GenericObjectSetPhase) GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus {
+	return a.Status.Phases
+}
+
+func (a *GenericObjectSetPhase) SetStatusPhases(phases []packagesv1alpha1.ObjectPhaseStatus) {
+	a.Status.Phases = phases
+}
+
 func (a *GenericObjectSetPhase) GetReadinessProbes() []packagesv1alpha1.ObjectSetProbe {
 	return a.Spec.ReadinessProbes
 }
 
+func (a *GenericObjectSetPhase) GetApplyMode() packagesv1alpha1.ObjectSetApplyMode {
+	return a.Spec.ApplyMode
+}
+
+func (a *GenericObjectSetPhase) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return a.Spec.DriftPolicy
+}
+
+func (a *GenericObjectSetPhase) GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy {
+	return a.Spec.AdoptionPolicy
+}
+
+func (a *GenericObjectSetPhase) GetStatusDrift() *packagesv1alpha1.ObjectSetDriftStatus {
+	return a.Status.Drift
+}
+
+func (a *GenericObjectSetPhase) SetStatusDrift(drift *packagesv1alpha1.ObjectSetDriftStatus) {
+	a.Status.Drift = drift
+}
+
 func (a *GenericObjectSetPhase) GetPhase() packagesv1alpha1.ObjectPhase {
 	return a.Spec.ObjectPhase
 }
@@ -75,6 +111,10 @@ func (a *GenericObjectSetPhase) IsPaused() bool {
 	return a.Spec.Paused
 }
 
+func (a *GenericObjectSetPhase) IsOrphaned() bool {
+	return a.Spec.Orphaned
+}
+
 func (a *GenericObjectSetPhase) GetClass() string {
 	return a.Spec.Class
 }
@@ -104,10 +144,38 @@ func (a *GenericClusterObjectSetPhase) SetStatusPausedFor(
 	a.Status.PausedFor = pausedFor
 }
 
+func (a *GenericClusterObjectSetPhase) GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus {
+	return a.Status.Phases
+}
+
+func (a *GenericClusterObjectSetPhase) SetStatusPhases(phases []packagesv1alpha1.ObjectPhaseStatus) {
+	a.Status.Phases = phases
+}
+
 func (a *GenericClusterObjectSetPhase) GetReadinessProbes() []packagesv1alpha1.ObjectSetProbe {
 	return a.Spec.ReadinessProbes
 }
 
+func (a *GenericClusterObjectSetPhase) GetApplyMode() packagesv1alpha1.ObjectSetApplyMode {
+	return a.Spec.ApplyMode
+}
+
+func (a *GenericClusterObjectSetPhase) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return a.Spec.DriftPolicy
+}
+
+func (a *GenericClusterObjectSetPhase) GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy {
+	return a.Spec.AdoptionPolicy
+}
+
+func (a *GenericClusterObjectSetPhase) GetStatusDrift() *packagesv1alpha1.ObjectSetDriftStatus {
+	return a.Status.Drift
+}
+
+func (a *GenericClusterObjectSetPhase) SetStatusDrift(drift *packagesv1alpha1.ObjectSetDriftStatus) {
+	a.Status.Drift = drift
+}
+
 func (a *GenericClusterObjectSetPhase) GetPhase() packagesv1alpha1.ObjectPhase {
 	return a.Spec.ObjectPhase
 }
@@ -128,6 +196,10 @@ func (a *GenericClusterObjectSetPhase) IsPaused() bool {
 	return a.Spec.Paused
 }
 
+func (a *GenericClusterObjectSetPhase) IsOrphaned() bool {
+	return a.Spec.Orphaned
+}
+
 func (a *GenericClusterObjectSetPhase) GetClass() string {
 	return a.Spec.Class
 }
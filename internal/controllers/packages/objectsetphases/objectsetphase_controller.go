@@ -8,9 +8,11 @@ import (
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers"
 	"github.com/thetechnick/package-operator/internal/controllers/packages"
+	"github.com/thetechnick/package-operator/internal/metrics"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -34,6 +36,8 @@ type GenericObjectSetPhaseController struct {
 type ownerStrategy interface {
 	IsOwner(owner, obj metav1.Object) bool
 	ReleaseController(obj metav1.Object)
+	GetController(obj metav1.Object) (metav1.OwnerReference, bool)
+	RemoveOwner(owner, obj metav1.Object)
 	SetControllerReference(owner, obj metav1.Object, scheme *runtime.Scheme) error
 	EnqueueRequestForOwner(ownerType client.Object, isController bool) handler.EventHandler
 }
@@ -53,11 +57,15 @@ func NewObjectSetPhaseController(
 	c, targetClient client.Client,
 	log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicWatcher,
+	recorder, targetRecorder record.EventRecorder,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	phaseConcurrency int,
 ) *GenericObjectSetPhaseController {
 	return NewGenericObjectSetPhaseController(
 		class, ownerStrategy,
 		packagesv1alpha1.GroupVersion.WithKind("ObjectSetPhase"),
-		c, targetClient, log, scheme, dw,
+		c, targetClient, log, scheme, dw, recorder, targetRecorder,
+		defaultApplyMode, phaseConcurrency,
 	)
 }
 
@@ -66,11 +74,15 @@ func NewClusterObjectSetPhaseController(
 	c, targetClient client.Client,
 	log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicWatcher,
+	recorder, targetRecorder record.EventRecorder,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	phaseConcurrency int,
 ) *GenericObjectSetPhaseController {
 	return NewGenericObjectSetPhaseController(
 		class, ownerStrategy,
 		packagesv1alpha1.GroupVersion.WithKind("ClusterObjectSetPhase"),
-		c, targetClient, log, scheme, dw,
+		c, targetClient, log, scheme, dw, recorder, targetRecorder,
+		defaultApplyMode, phaseConcurrency,
 	)
 }
 
@@ -80,6 +92,9 @@ func NewGenericObjectSetPhaseController(
 	c, targetClient client.Client,
 	log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicWatcher,
+	recorder, targetRecorder record.EventRecorder,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	phaseConcurrency int,
 ) *GenericObjectSetPhaseController {
 	controller := &GenericObjectSetPhaseController{
 		gvk:   gvk,
@@ -95,8 +110,10 @@ func NewGenericObjectSetPhaseController(
 
 	controller.reconciler = []reconciler{
 		&PhaseReconciler{
+			recorder: recorder,
 			phaseReconciler: packages.NewPhaseReconciler(
-				dw, targetClient, scheme, ownerStrategy),
+				dw, targetClient, c, scheme, recorder, targetRecorder, ownerStrategy,
+				defaultApplyMode, phaseConcurrency),
 		},
 	}
 
@@ -166,16 +183,28 @@ func (c *GenericObjectSetPhaseController) newOperand() genericObjectSetPhase {
 func (c *GenericObjectSetPhaseController) handleDeletion(
 	ctx context.Context, objectSetPhase genericObjectSetPhase,
 ) error {
-	done, err := packages.TeardownPhase(ctx, c.targetClient, objectSetPhase, objectSetPhase.GetPhase())
+	teardown := packages.TeardownPhase
+	if objectSetPhase.IsOrphaned() {
+		teardown = packages.OrphanPhase
+	}
+	result, err := teardown(
+		ctx, c.targetClient, c.client, c.ownerStrategy, objectSetPhase, objectSetPhase.GetPhase())
 	if err != nil {
 		return fmt.Errorf("tearing down ObjectSetPhase: %w", err)
 	}
 
-	if !done {
+	if !result.Done {
+		if len(result.Terminating) > 0 {
+			controllers.LoggerFromContext(ctx).Info(
+				"waiting for objects to terminate", "objects", result.Message())
+		}
 		// wait till we remove our finalizer
 		return nil
 	}
 
+	obj := objectSetPhase.ClientObject()
+	metrics.DeletePhaseMetrics(
+		c.gvk.Kind, obj.GetNamespace(), obj.GetName(), objectSetPhase.GetPhase().Name)
 	return controllers.HandleCommonDeletion(ctx, objectSetPhase.ClientObject(), c.client, c.dw, packages.CacheFinalizer)
 }
 
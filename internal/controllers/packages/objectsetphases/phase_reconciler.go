@@ -3,51 +3,101 @@ package objectsetphases
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers/packages"
 	internalprobe "github.com/thetechnick/package-operator/internal/probe"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 type PhaseReconciler struct {
+	recorder        record.EventRecorder
 	phaseReconciler phaseReconciler
 }
 
 type phaseReconciler interface {
 	Reconcile(
 		ctx context.Context,
-		owner packages.PausingClientObject,
+		owner packages.PhaseOwner,
 		phase packagesv1alpha1.ObjectPhase,
 		probe internalprobe.Interface,
-	) (failedProbes []string, err error)
+	) (packages.PhaseProbeResult, error)
 }
 
 func (r *PhaseReconciler) Reconcile(
 	ctx context.Context,
 	objectSetPhase genericObjectSetPhase,
 ) (ctrl.Result, error) {
-	probe := packages.ParseProbes(objectSetPhase.GetReadinessProbes())
-	phase := objectSetPhase.GetPhase()
+	probe, err := packages.ParseProbes(objectSetPhase.GetReadinessProbes())
+	if err != nil {
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetProbesInvalid,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ProbesInvalid",
+			Message:            err.Error(),
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ProbesInvalid",
+			Message:            "Readiness probes are invalid.",
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+		// Nothing we can do until the spec is fixed.
+		return ctrl.Result{}, nil
+	}
+	meta.RemoveStatusCondition(objectSetPhase.GetConditions(), packagesv1alpha1.ObjectSetProbesInvalid)
 
-	var (
-		failedProbes []string
-		err          error
-	)
-	failedProbes, err = r.phaseReconciler.Reconcile(ctx, objectSetPhase, phase, probe)
+	if err := packages.ValidatePausedObjects(objectSetPhase.GetPausedFor()); err != nil {
+		if !meta.IsStatusConditionTrue(*objectSetPhase.GetConditions(), packagesv1alpha1.ObjectSetPausedForInvalid) {
+			r.recorder.Event(objectSetPhase.ClientObject(), corev1.EventTypeWarning, "PausedForInvalid", err.Error())
+		}
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetPausedForInvalid,
+			Status:             metav1.ConditionTrue,
+			Reason:             "PausedForInvalid",
+			Message:            err.Error(),
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "PausedForInvalid",
+			Message:            "Paused objects are invalid.",
+			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
+		})
+		// Nothing we can do until the spec is fixed.
+		return ctrl.Result{}, nil
+	}
+	meta.RemoveStatusCondition(objectSetPhase.GetConditions(), packagesv1alpha1.ObjectSetPausedForInvalid)
+
+	phase := objectSetPhase.GetPhase()
+	result, err := r.phaseReconciler.Reconcile(ctx, objectSetPhase, phase, probe)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if len(failedProbes) > 0 {
+	now := metav1.Now()
+	objectSetPhase.SetStatusPhases([]packagesv1alpha1.ObjectPhaseStatus{
+		packages.NewPhaseStatus(
+			objectSetPhase.GetStatusPhases(), phase.Name, result.Ready(), result, now),
+	})
+	objectSetPhase.SetStatusDrift(packages.NewDriftStatus(
+		objectSetPhase.GetStatusDrift(), []packages.PhaseProbeResult{result}, now))
+	packages.SetConflictConditions(
+		objectSetPhase.GetConditions(), objectSetPhase.ClientObject().GetGeneration(),
+		[]packages.PhaseProbeResult{result})
+	if !result.Ready() {
 		meta.SetStatusCondition(objectSetPhase.GetConditions(), metav1.Condition{
 			Type:               packagesv1alpha1.ObjectSetAvailable,
 			Status:             metav1.ConditionFalse,
 			Reason:             "ProbeFailure",
-			Message:            fmt.Sprintf("Phase %q failed: %s", phase.Name, strings.Join(failedProbes, ", ")),
+			Message:            fmt.Sprintf("Phase %q failed: %s", phase.Name, result.Message()),
 			ObservedGeneration: objectSetPhase.ClientObject().GetGeneration(),
 		})
 		return ctrl.Result{}, nil
@@ -12,14 +12,25 @@ import (
 type genericObjectSet interface {
 	ClientObject() client.Object
 	UpdatePhase()
+	GetStatusPhase() packagesv1alpha1.ObjectSetStatusPhase
 	GetConditions() *[]metav1.Condition
 	GetPhases() []packagesv1alpha1.ObjectPhase
 	IsArchived() bool
+	IsOrphaned() bool
 	IsPaused() bool
 	IsObjectPaused(obj client.Object) bool
 	GetPausedFor() []packagesv1alpha1.ObjectSetPausedObject
 	SetStatusPausedFor(pausedFor []packagesv1alpha1.ObjectSetPausedObject)
 	GetReadinessProbes() []packagesv1alpha1.ObjectSetProbe
+	GetApplyMode() packagesv1alpha1.ObjectSetApplyMode
+	GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy
+	GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy
+	GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus
+	SetStatusPhases(phases []packagesv1alpha1.ObjectPhaseStatus)
+	GetStatusDrift() *packagesv1alpha1.ObjectSetDriftStatus
+	SetStatusDrift(drift *packagesv1alpha1.ObjectSetDriftStatus)
+	GetStatusHooks() []packagesv1alpha1.HookStatus
+	SetStatusHooks(hooks []packagesv1alpha1.HookStatus)
 }
 
 var (
@@ -35,6 +46,18 @@ func (a *GenericObjectSet) GetReadinessProbes() []packagesv1alpha1.ObjectSetProb
 	return a.Spec.ReadinessProbes
 }
 
+func (a *GenericObjectSet) GetApplyMode() packagesv1alpha1.ObjectSetApplyMode {
+	return a.Spec.ApplyMode
+}
+
+func (a *GenericObjectSet) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return a.Spec.DriftPolicy
+}
+
+func (a *GenericObjectSet) GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy {
+	return a.Spec.AdoptionPolicy
+}
+
 func (a *GenericObjectSet) ClientObject() client.Object {
 	return &a.ObjectSet
 }
@@ -52,6 +75,30 @@ func (a *GenericObjectSet) SetStatusPausedFor(
 	a.Status.PausedFor = pausedFor
 }
 
+func (a *GenericObjectSet) GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus {
+	return a.Status.Phases
+}
+
+func (a *GenericObjectSet) SetStatusPhases(phases []packagesv1alpha1.ObjectPhaseStatus) {
+	a.Status.Phases = phases
+}
+
+func (a *GenericObjectSet) GetStatusDrift() *packagesv1alpha1.ObjectSetDriftStatus {
+	return a.Status.Drift
+}
+
+func (a *GenericObjectSet) SetStatusDrift(drift *packagesv1alpha1.ObjectSetDriftStatus) {
+	a.Status.Drift = drift
+}
+
+func (a *GenericObjectSet) GetStatusHooks() []packagesv1alpha1.HookStatus {
+	return a.Status.Hooks
+}
+
+func (a *GenericObjectSet) SetStatusHooks(hooks []packagesv1alpha1.HookStatus) {
+	a.Status.Hooks = hooks
+}
+
 func (a *GenericObjectSet) IsObjectPaused(obj client.Object) bool {
 	if a.IsPaused() {
 		return true
@@ -87,8 +134,18 @@ func (a *GenericObjectSet) UpdatePhase() {
 	a.Status.Phase = packagesv1alpha1.ObjectSetPhaseNotReady
 }
 
+// Orphaned ObjectSets are archived as well, but don't delete their objects.
 func (a *GenericObjectSet) IsArchived() bool {
-	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateArchived
+	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateArchived ||
+		a.IsOrphaned()
+}
+
+func (a *GenericObjectSet) GetStatusPhase() packagesv1alpha1.ObjectSetStatusPhase {
+	return a.Status.Phase
+}
+
+func (a *GenericObjectSet) IsOrphaned() bool {
+	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateOrphaned
 }
 
 func (a *GenericObjectSet) GetPhases() []packagesv1alpha1.ObjectPhase {
@@ -107,6 +164,18 @@ func (a *GenericClusterObjectSet) GetReadinessProbes() []packagesv1alpha1.Object
 	return a.Spec.ReadinessProbes
 }
 
+func (a *GenericClusterObjectSet) GetApplyMode() packagesv1alpha1.ObjectSetApplyMode {
+	return a.Spec.ApplyMode
+}
+
+func (a *GenericClusterObjectSet) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return a.Spec.DriftPolicy
+}
+
+func (a *GenericClusterObjectSet) GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy {
+	return a.Spec.AdoptionPolicy
+}
+
 func (a *GenericClusterObjectSet) ClientObject() client.Object {
 	return &a.ClusterObjectSet
 }
@@ -142,8 +211,18 @@ func (a *GenericClusterObjectSet) GetPausedFor() []packagesv1alpha1.ObjectSetPau
 	return a.Spec.PausedFor
 }
 
+// Orphaned ObjectSets are archived as well, but don't delete their objects.
 func (a *GenericClusterObjectSet) IsArchived() bool {
-	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateArchived
+	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateArchived ||
+		a.IsOrphaned()
+}
+
+func (a *GenericClusterObjectSet) GetStatusPhase() packagesv1alpha1.ObjectSetStatusPhase {
+	return a.Status.Phase
+}
+
+func (a *GenericClusterObjectSet) IsOrphaned() bool {
+	return a.Spec.LifecycleState == packagesv1alpha1.ObjectSetLifecycleStateOrphaned
 }
 
 func (a *GenericClusterObjectSet) GetPhases() []packagesv1alpha1.ObjectPhase {
@@ -171,13 +250,46 @@ func (a *GenericClusterObjectSet) SetStatusPausedFor(
 	a.Status.PausedFor = pausedFor
 }
 
+func (a *GenericClusterObjectSet) GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus {
+	return a.Status.Phases
+}
+
+func (a *GenericClusterObjectSet) SetStatusPhases(phases []packagesv1alpha1.ObjectPhaseStatus) {
+	a.Status.Phases = phases
+}
+
+func (a *GenericClusterObjectSet) GetStatusDrift() *packagesv1alpha1.ObjectSetDriftStatus {
+	return a.Status.Drift
+}
+
+func (a *GenericClusterObjectSet) SetStatusDrift(drift *packagesv1alpha1.ObjectSetDriftStatus) {
+	a.Status.Drift = drift
+}
+
+func (a *GenericClusterObjectSet) GetStatusHooks() []packagesv1alpha1.HookStatus {
+	return a.Status.Hooks
+}
+
+func (a *GenericClusterObjectSet) SetStatusHooks(hooks []packagesv1alpha1.HookStatus) {
+	a.Status.Hooks = hooks
+}
+
 type genericObjectSetPhase interface {
 	ClientObject() client.Object
 	GetConditions() []metav1.Condition
 	SetPhase(phase packagesv1alpha1.ObjectPhase)
 	SetReadinessProbes(probes []packagesv1alpha1.ObjectSetProbe)
+	SetApplyMode(applyMode packagesv1alpha1.ObjectSetApplyMode)
+	SetDriftPolicy(driftPolicy packagesv1alpha1.ObjectSetDriftPolicy)
+	SetAdoptionPolicy(adoptionPolicy packagesv1alpha1.ObjectSetAdoptionPolicy)
+	GetApplyMode() packagesv1alpha1.ObjectSetApplyMode
+	GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy
+	GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy
 	GetStatusPausedFor() []packagesv1alpha1.ObjectSetPausedObject
+	GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus
 	SetSpecPausedFor(pausedFor []packagesv1alpha1.ObjectSetPausedObject)
+	IsSpecOrphaned() bool
+	SetSpecOrphaned(orphaned bool)
 }
 
 var (
@@ -205,6 +317,30 @@ func (a *GenericObjectSetPhase) SetReadinessProbes(probes []packagesv1alpha1.Obj
 	a.Spec.ReadinessProbes = probes
 }
 
+func (a *GenericObjectSetPhase) SetApplyMode(applyMode packagesv1alpha1.ObjectSetApplyMode) {
+	a.Spec.ApplyMode = applyMode
+}
+
+func (a *GenericObjectSetPhase) SetDriftPolicy(driftPolicy packagesv1alpha1.ObjectSetDriftPolicy) {
+	a.Spec.DriftPolicy = driftPolicy
+}
+
+func (a *GenericObjectSetPhase) SetAdoptionPolicy(adoptionPolicy packagesv1alpha1.ObjectSetAdoptionPolicy) {
+	a.Spec.AdoptionPolicy = adoptionPolicy
+}
+
+func (a *GenericObjectSetPhase) GetApplyMode() packagesv1alpha1.ObjectSetApplyMode {
+	return a.Spec.ApplyMode
+}
+
+func (a *GenericObjectSetPhase) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return a.Spec.DriftPolicy
+}
+
+func (a *GenericObjectSetPhase) GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy {
+	return a.Spec.AdoptionPolicy
+}
+
 func (a *GenericObjectSetPhase) SetSpecPausedFor(paused []packagesv1alpha1.ObjectSetPausedObject) {
 	a.Spec.PausedFor = paused
 }
@@ -213,6 +349,18 @@ func (a *GenericObjectSetPhase) GetStatusPausedFor() []packagesv1alpha1.ObjectSe
 	return a.Status.PausedFor
 }
 
+func (a *GenericObjectSetPhase) IsSpecOrphaned() bool {
+	return a.Spec.Orphaned
+}
+
+func (a *GenericObjectSetPhase) SetSpecOrphaned(orphaned bool) {
+	a.Spec.Orphaned = orphaned
+}
+
+func (a *GenericObjectSetPhase) GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus {
+	return a.Status.Phases
+}
+
 type GenericClusterObjectSetPhase struct {
 	packagesv1alpha1.ClusterObjectSetPhase
 }
@@ -233,6 +381,30 @@ func (a *GenericClusterObjectSetPhase) SetReadinessProbes(probes []packagesv1alp
 	a.Spec.ReadinessProbes = probes
 }
 
+func (a *GenericClusterObjectSetPhase) SetApplyMode(applyMode packagesv1alpha1.ObjectSetApplyMode) {
+	a.Spec.ApplyMode = applyMode
+}
+
+func (a *GenericClusterObjectSetPhase) SetDriftPolicy(driftPolicy packagesv1alpha1.ObjectSetDriftPolicy) {
+	a.Spec.DriftPolicy = driftPolicy
+}
+
+func (a *GenericClusterObjectSetPhase) SetAdoptionPolicy(adoptionPolicy packagesv1alpha1.ObjectSetAdoptionPolicy) {
+	a.Spec.AdoptionPolicy = adoptionPolicy
+}
+
+func (a *GenericClusterObjectSetPhase) GetApplyMode() packagesv1alpha1.ObjectSetApplyMode {
+	return a.Spec.ApplyMode
+}
+
+func (a *GenericClusterObjectSetPhase) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return a.Spec.DriftPolicy
+}
+
+func (a *GenericClusterObjectSetPhase) GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy {
+	return a.Spec.AdoptionPolicy
+}
+
 func (a *GenericClusterObjectSetPhase) SetSpecPausedFor(paused []packagesv1alpha1.ObjectSetPausedObject) {
 	a.Spec.PausedFor = paused
 }
@@ -240,3 +412,15 @@ func (a *GenericClusterObjectSetPhase) SetSpecPausedFor(paused []packagesv1alpha
 func (a *GenericClusterObjectSetPhase) GetStatusPausedFor() []packagesv1alpha1.ObjectSetPausedObject {
 	return a.Status.PausedFor
 }
+
+func (a *GenericClusterObjectSetPhase) IsSpecOrphaned() bool {
+	return a.Spec.Orphaned
+}
+
+func (a *GenericClusterObjectSetPhase) SetSpecOrphaned(orphaned bool) {
+	a.Spec.Orphaned = orphaned
+}
+
+func (a *GenericClusterObjectSetPhase) GetStatusPhases() []packagesv1alpha1.ObjectPhaseStatus {
+	return a.Status.Phases
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -18,44 +21,63 @@ type dynamicWatchFreer interface {
 type ArchivedObjectSetReconciler struct {
 	teardownHandler teardownHandler
 	dw              dynamicWatchFreer
+	recorder        record.EventRecorder
 }
 
 type teardownHandler interface {
 	Teardown(
 		ctx context.Context, objectSet genericObjectSet,
-	) (done bool, err error)
+	) (packages.TeardownResult, error)
 }
 
 func (r *ArchivedObjectSetReconciler) Reconcile(ctx context.Context, objectSet genericObjectSet) (ctrl.Result, error) {
 	if !objectSet.IsArchived() {
+		// ObjectSets may be reactivated by rolling back their ObjectDeployment.
+		meta.RemoveStatusCondition(objectSet.GetConditions(), packagesv1alpha1.ObjectSetArchived)
 		return ctrl.Result{}, nil
 	}
 
-	done, err := r.teardownHandler.Teardown(ctx, objectSet)
+	reason, tearingDownMsg, archivedMsg := "Archived", "ObjectSet is tearing down.", "ObjectSet is archived."
+	if objectSet.IsOrphaned() {
+		reason, tearingDownMsg, archivedMsg = "Orphaned", "ObjectSet is releasing objects.", "ObjectSet is orphaned."
+	}
+
+	result, err := r.teardownHandler.Teardown(ctx, objectSet)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("archiving ObjectSet: %w", err)
 	}
-	if !done {
+	if !result.Done {
+		if result.Stuck() {
+			reason = "TeardownStuck"
+		}
+		if len(result.Terminating) > 0 {
+			tearingDownMsg += " Terminating: " + result.Message()
+		}
 		conditions := objectSet.GetConditions()
 		meta.RemoveStatusCondition(conditions, packagesv1alpha1.ObjectSetPaused)
 		meta.RemoveStatusCondition(conditions, packagesv1alpha1.ObjectSetAvailable)
 		meta.SetStatusCondition(conditions, metav1.Condition{
 			Type:               packagesv1alpha1.ObjectSetArchived,
 			Status:             metav1.ConditionFalse,
-			Reason:             "Archived",
-			Message:            "ObjectSet is tearing down.",
+			Reason:             reason,
+			Message:            tearingDownMsg,
 			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
 		})
+		// Terminating objects are watched, requeue only to report them as stuck.
+		return ctrl.Result{RequeueAfter: result.RequeueAfter}, nil
 	}
 
 	conditions := objectSet.GetConditions()
+	if !meta.IsStatusConditionTrue(*conditions, packagesv1alpha1.ObjectSetArchived) {
+		r.recorder.Event(objectSet.ClientObject(), corev1.EventTypeNormal, reason, archivedMsg)
+	}
 	meta.RemoveStatusCondition(conditions, packagesv1alpha1.ObjectSetPaused)
 	meta.RemoveStatusCondition(conditions, packagesv1alpha1.ObjectSetAvailable)
 	meta.SetStatusCondition(conditions, metav1.Condition{
 		Type:               packagesv1alpha1.ObjectSetArchived,
 		Status:             metav1.ConditionTrue,
-		Reason:             "Archived",
-		Message:            "ObjectSet is archived.",
+		Reason:             reason,
+		Message:            archivedMsg,
 		ObservedGeneration: objectSet.ClientObject().GetGeneration(),
 	})
 
@@ -0,0 +1,222 @@
+package objectsets
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
+)
+
+// Runs hook Jobs of ObjectSets.
+type HookRunner struct {
+	client client.Client
+	scheme *runtime.Scheme
+	dw     dynamicObjectWatcher
+}
+
+func NewHookRunner(
+	c client.Client, scheme *runtime.Scheme, dw dynamicObjectWatcher,
+) *HookRunner {
+	return &HookRunner{
+		client: c,
+		scheme: scheme,
+		dw:     dw,
+	}
+}
+
+type hookResult struct {
+	// True when all hooks succeeded.
+	done bool
+	// True when a hook failed.
+	failed bool
+	// True when a hook Job name is taken by a foreign object.
+	conflict bool
+	message  string
+}
+
+// Runs the hooks of the given type one after another,
+// recording their results in the ObjectSet status.
+// Hooks are not run again after they succeeded.
+// Failed hooks are retried, when their Job is deleted.
+func (h *HookRunner) Run(
+	ctx context.Context, objectSet genericObjectSet,
+	hookType packagesv1alpha1.HookType,
+) (hookResult, error) {
+	hooks, err := packages.PhaseHooks(objectSet.GetPhases())
+	if err != nil {
+		return hookResult{}, err
+	}
+
+	for _, hook := range hooks {
+		if hook.Type != hookType {
+			continue
+		}
+
+		status := findHookStatus(objectSet.GetStatusHooks(), hookType, hook.Object.GetName())
+		if status == nil || status.State != packagesv1alpha1.HookStateSucceeded {
+			newStatus, err := h.runHook(ctx, objectSet, hook)
+			if err != nil {
+				return hookResult{}, fmt.Errorf("running %s hook %q: %w", hookType, hook.Object.GetName(), err)
+			}
+			setHookStatus(objectSet, newStatus)
+			status = &newStatus
+		}
+
+		switch status.State {
+		case packagesv1alpha1.HookStateSucceeded:
+			continue
+		case packagesv1alpha1.HookStateFailed:
+			return hookResult{
+				failed: true,
+				message: fmt.Sprintf("%s hook %q failed: %s",
+					hookType, hook.Object.GetName(), status.Message),
+			}, nil
+		case packagesv1alpha1.HookStateConflict:
+			return hookResult{
+				conflict: true,
+				message: fmt.Sprintf("%s hook %q conflicts with existing object: %s",
+					hookType, hook.Object.GetName(), status.Message),
+			}, nil
+		}
+		return hookResult{
+			message: fmt.Sprintf("Waiting for %s hook %q to complete.", hookType, hook.Object.GetName()),
+		}, nil
+	}
+	return hookResult{done: true}, nil
+}
+
+// Ensures the hook Job exists and reports its state.
+func (h *HookRunner) runHook(
+	ctx context.Context, objectSet genericObjectSet, hook packages.Hook,
+) (packagesv1alpha1.HookStatus, error) {
+	log := controllers.LoggerFromContext(ctx)
+	owner := objectSet.ClientObject()
+	obj := hook.Object
+	status := packagesv1alpha1.HookStatus{
+		Name:  obj.GetName(),
+		Type:  hook.Type,
+		State: packagesv1alpha1.HookStateRunning,
+	}
+
+	if len(obj.GetNamespace()) == 0 {
+		obj.SetNamespace(owner.GetNamespace())
+	}
+	if err := controllerutil.SetControllerReference(owner, obj, h.scheme); err != nil {
+		return status, err
+	}
+	if err := h.dw.Watch(owner, obj); err != nil {
+		return status, fmt.Errorf("watching hook: %w", err)
+	}
+
+	currentObj := &unstructured.Unstructured{}
+	currentObj.SetGroupVersionKind(obj.GroupVersionKind())
+	err := h.client.Get(ctx, client.ObjectKeyFromObject(obj), currentObj)
+	if errors.IsNotFound(err) {
+		log.Info("creating hook", "type", hook.Type, "obj", client.ObjectKeyFromObject(obj))
+		if err := h.client.Create(ctx, obj); err != nil {
+			return status, fmt.Errorf("creating: %w", err)
+		}
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("getting: %w", err)
+	}
+
+	if !metav1.IsControlledBy(currentObj, owner) {
+		// Only Jobs of other revisions may be replaced.
+		conflict := "object is not controlled by package-operator"
+		if controller := metav1.GetControllerOf(currentObj); controller != nil {
+			isSibling, err := packages.IsSiblingOwner(ctx, h.client, h.scheme, owner, *controller)
+			if err != nil {
+				return status, fmt.Errorf("checking controller of existing hook: %w", err)
+			}
+			if isSibling {
+				conflict = ""
+			} else {
+				conflict = fmt.Sprintf("controlled by %s %s %q",
+					controller.APIVersion, controller.Kind, controller.Name)
+			}
+		}
+		if len(conflict) > 0 {
+			status.State = packagesv1alpha1.HookStateConflict
+			status.Message = conflict
+			return status, nil
+		}
+
+		// Left over from a previous revision,
+		// delete it to run the hook again for this revision.
+		if currentObj.GetDeletionTimestamp() == nil {
+			log.Info("deleting previous hook", "type", hook.Type, "obj", client.ObjectKeyFromObject(obj))
+			uid := currentObj.GetUID()
+			if err := h.client.Delete(ctx, currentObj,
+				client.Preconditions{UID: &uid},
+				client.PropagationPolicy(metav1.DeletePropagationBackground),
+			); err != nil && !errors.IsNotFound(err) {
+				return status, fmt.Errorf("deleting previous hook: %w", err)
+			}
+		}
+		return status, nil
+	}
+
+	job := &batchv1.Job{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+		currentObj.Object, job); err != nil {
+		return status, fmt.Errorf("converting to Job: %w", err)
+	}
+	for i := range job.Status.Conditions {
+		cond := &job.Status.Conditions[i]
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			status.State = packagesv1alpha1.HookStateSucceeded
+			status.CompletionTime = &cond.LastTransitionTime
+		case batchv1.JobFailed:
+			status.State = packagesv1alpha1.HookStateFailed
+			status.Message = cond.Message
+			status.CompletionTime = &cond.LastTransitionTime
+		}
+	}
+	return status, nil
+}
+
+func findHookStatus(
+	statuses []packagesv1alpha1.HookStatus,
+	hookType packagesv1alpha1.HookType, name string,
+) *packagesv1alpha1.HookStatus {
+	for i := range statuses {
+		if statuses[i].Type == hookType && statuses[i].Name == name {
+			return &statuses[i]
+		}
+	}
+	return nil
+}
+
+func setHookStatus(objectSet genericObjectSet, status packagesv1alpha1.HookStatus) {
+	statuses := objectSet.GetStatusHooks()
+	if existing := findHookStatus(statuses, status.Type, status.Name); existing != nil {
+		*existing = status
+		return
+	}
+	objectSet.SetStatusHooks(append(statuses, status))
+}
+
+// Returns true if the ObjectSet is a later revision of an ObjectDeployment.
+func isUpgrade(objectSet genericObjectSet) bool {
+	revision, _ := strconv.Atoi(
+		objectSet.ClientObject().GetAnnotations()[packagesv1alpha1.ObjectSetRevisionAnnotation])
+	return revision > 1
+}
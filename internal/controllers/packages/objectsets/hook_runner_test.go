@@ -0,0 +1,207 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/testutil"
+)
+
+type dynamicObjectWatcherMock struct{}
+
+func (dynamicObjectWatcherMock) Watch(owner client.Object, obj runtime.Object) error { return nil }
+
+func TestHookRunner_Run(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+
+	deploymentRef := metav1.OwnerReference{
+		APIVersion: packagesv1alpha1.GroupVersion.String(), Kind: "ObjectDeployment",
+		Name: "test", UID: "test-deployment-uid", Controller: pointer.BoolPtr(true),
+	}
+	jobCondition := func(condType batchv1.JobConditionType) *batchv1.JobCondition {
+		return &batchv1.JobCondition{
+			Type: condType, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded",
+		}
+	}
+
+	tests := []struct {
+		name string
+		// previous state of the hook, if any.
+		state packagesv1alpha1.HookState
+		// whether the hook Job exists and its condition, if any.
+		jobExists    bool
+		jobCondition *batchv1.JobCondition
+		// controller of the hook Job, if not the ObjectSet itself.
+		jobController *metav1.OwnerReference
+
+		expectedResult hookResult
+		expectedState  packagesv1alpha1.HookState
+		expectedCreate bool
+		expectedDelete bool
+	}{
+		{
+			name: "pending",
+			expectedResult: hookResult{
+				message: `Waiting for pre-delete hook "backup" to complete.`,
+			},
+			expectedState:  packagesv1alpha1.HookStateRunning,
+			expectedCreate: true,
+		},
+		{
+			name:      "running",
+			state:     packagesv1alpha1.HookStateRunning,
+			jobExists: true,
+			expectedResult: hookResult{
+				message: `Waiting for pre-delete hook "backup" to complete.`,
+			},
+			expectedState: packagesv1alpha1.HookStateRunning,
+		},
+		{
+			name:           "succeeded",
+			state:          packagesv1alpha1.HookStateRunning,
+			jobExists:      true,
+			jobCondition:   jobCondition(batchv1.JobComplete),
+			expectedResult: hookResult{done: true},
+			expectedState:  packagesv1alpha1.HookStateSucceeded,
+		},
+		{
+			name:         "failed",
+			state:        packagesv1alpha1.HookStateFailed,
+			jobExists:    true,
+			jobCondition: jobCondition(batchv1.JobFailed),
+			expectedResult: hookResult{
+				failed:  true,
+				message: `pre-delete hook "backup" failed: BackoffLimitExceeded`,
+			},
+			expectedState: packagesv1alpha1.HookStateFailed,
+		},
+		{
+			name:  "retried",
+			state: packagesv1alpha1.HookStateFailed,
+			expectedResult: hookResult{
+				message: `Waiting for pre-delete hook "backup" to complete.`,
+			},
+			expectedState:  packagesv1alpha1.HookStateRunning,
+			expectedCreate: true,
+		},
+		{
+			name:      "previous revision",
+			jobExists: true,
+			jobController: &metav1.OwnerReference{
+				APIVersion: packagesv1alpha1.GroupVersion.String(), Kind: "ObjectSet",
+				Name: "test-previous", UID: "test-previous-uid", Controller: pointer.BoolPtr(true),
+			},
+			expectedResult: hookResult{
+				message: `Waiting for pre-delete hook "backup" to complete.`,
+			},
+			expectedState:  packagesv1alpha1.HookStateRunning,
+			expectedDelete: true,
+		},
+		{
+			name:      "conflict",
+			jobExists: true,
+			jobController: &metav1.OwnerReference{
+				APIVersion: "apps/v1", Kind: "Deployment",
+				Name: "backup", UID: "backup-uid", Controller: pointer.BoolPtr(true),
+			},
+			expectedResult: hookResult{
+				conflict: true,
+				message: `pre-delete hook "backup" conflicts with existing object: ` +
+					`controlled by apps/v1 Deployment "backup"`,
+			},
+			expectedState: packagesv1alpha1.HookStateConflict,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objectSet := &GenericObjectSet{}
+			objectSet.Name = "test"
+			objectSet.Namespace = "test"
+			objectSet.UID = "test-uid"
+			objectSet.OwnerReferences = []metav1.OwnerReference{deploymentRef}
+			objectSet.Spec.Phases = []packagesv1alpha1.ObjectPhase{{
+				Name: "test",
+				Objects: []packagesv1alpha1.ObjectSetObject{{
+					Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"batch/v1","kind":"Job",` +
+						`"metadata":{"name":"backup","annotations":{"packages.thetechnick.ninja/hook":"pre-delete"}}}`)},
+				}},
+			}}
+			if len(test.state) > 0 {
+				objectSet.Status.Hooks = []packagesv1alpha1.HookStatus{{
+					Name: "backup", Type: packagesv1alpha1.HookPreDelete, State: test.state,
+				}}
+			}
+
+			var getErr error
+			if !test.jobExists {
+				getErr = errors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, "backup")
+			}
+			c := testutil.NewClient()
+			c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					if previous, ok := args.Get(2).(*packagesv1alpha1.ObjectSet); ok {
+						previous.Name = "test-previous"
+						previous.UID = "test-previous-uid"
+						previous.OwnerReferences = []metav1.OwnerReference{deploymentRef}
+						return
+					}
+					if !test.jobExists {
+						return
+					}
+					job := &batchv1.Job{}
+					job.Name = "backup"
+					job.Namespace = "test"
+					job.OwnerReferences = []metav1.OwnerReference{{
+						APIVersion: packagesv1alpha1.GroupVersion.String(), Kind: "ObjectSet",
+						Name: "test", UID: "test-uid", Controller: pointer.BoolPtr(true),
+					}}
+					if test.jobController != nil {
+						job.OwnerReferences = []metav1.OwnerReference{*test.jobController}
+					}
+					if test.jobCondition != nil {
+						job.Status.Conditions = []batchv1.JobCondition{*test.jobCondition}
+					}
+					obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(job)
+					require.NoError(t, err)
+					args.Get(2).(*unstructured.Unstructured).Object = obj
+				}).
+				Return(getErr)
+			c.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			c.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			h := NewHookRunner(c, scheme, dynamicObjectWatcherMock{})
+			result, err := h.Run(context.Background(), objectSet, packagesv1alpha1.HookPreDelete)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedResult, result)
+
+			if assert.Len(t, objectSet.Status.Hooks, 1) {
+				assert.Equal(t, test.expectedState, objectSet.Status.Hooks[0].State)
+			}
+			if test.expectedCreate {
+				c.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if test.expectedDelete {
+				c.AssertCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -8,9 +8,11 @@ import (
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers"
 	"github.com/thetechnick/package-operator/internal/controllers/packages"
+	"github.com/thetechnick/package-operator/internal/metrics"
 	"github.com/thetechnick/package-operator/internal/ownerhandling"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -43,22 +45,28 @@ type dynamicWatcher interface {
 func NewObjectSetController(
 	c client.Client, log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicWatcher,
+	recorder record.EventRecorder,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	phaseConcurrency int,
 ) *GenericObjectSetController {
 	return NewGenericObjectSetController(
 		packagesv1alpha1.GroupVersion.WithKind("ObjectSet"),
 		packagesv1alpha1.GroupVersion.WithKind("ObjectSetPhase"),
-		c, log, scheme, dw,
+		c, log, scheme, dw, recorder, defaultApplyMode, phaseConcurrency,
 	)
 }
 
 func NewClusterObjectSetController(
 	c client.Client, log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicWatcher,
+	recorder record.EventRecorder,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	phaseConcurrency int,
 ) *GenericObjectSetController {
 	return NewGenericObjectSetController(
 		packagesv1alpha1.GroupVersion.WithKind("ClusterObjectSet"),
 		packagesv1alpha1.GroupVersion.WithKind("ClusterObjectSetPhase"),
-		c, log, scheme, dw,
+		c, log, scheme, dw, recorder, defaultApplyMode, phaseConcurrency,
 	)
 }
 
@@ -67,6 +75,9 @@ func NewGenericObjectSetController(
 	phaseGVK schema.GroupVersionKind,
 	c client.Client, log logr.Logger,
 	scheme *runtime.Scheme, dw dynamicWatcher,
+	recorder record.EventRecorder,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	phaseConcurrency int,
 ) *GenericObjectSetController {
 	controller := &GenericObjectSetController{
 		gvk:      gvk,
@@ -78,19 +89,25 @@ func NewGenericObjectSetController(
 		dw:     dw,
 	}
 
-	controller.teardownHandler = NewTeardownHandler(c, dw, controller.newPhase)
+	hookRunner := NewHookRunner(c, scheme, dw)
+	controller.teardownHandler = NewTeardownHandler(c, scheme, dw, hookRunner, controller.newPhase)
 
 	controller.reconciler = []reconciler{
 		&ArchivedObjectSetReconciler{
 			dw:              dw,
+			recorder:        recorder,
 			teardownHandler: controller.teardownHandler,
 		},
 		&ObjectSetPhaseReconciler{
 			client:            c,
 			scheme:            scheme,
 			dw:                dw,
+			recorder:          recorder,
 			newObjectSetPhase: controller.newPhase,
-			phaseReconciler:   packages.NewPhaseReconciler(dw, c, scheme, ownerhandling.Native),
+			hookRunner:        hookRunner,
+			phaseReconciler: packages.NewPhaseReconciler(
+				dw, c, c, scheme, recorder, recorder, ownerhandling.Native,
+				defaultApplyMode, phaseConcurrency),
 		},
 	}
 
@@ -149,9 +166,48 @@ func (c *GenericObjectSetController) Reconcile(
 
 	objectSet.SetStatusPausedFor(objectSet.GetPausedFor()) // report paused objects via status
 	objectSet.UpdatePhase()
+	c.recordMetrics(objectSet)
 	return res, c.client.Status().Update(ctx, objectSet.ClientObject())
 }
 
+// Reports phase and managed objects of the ObjectSet.
+// Archived ObjectSets don't manage objects anymore.
+func (c *GenericObjectSetController) recordMetrics(objectSet genericObjectSet) {
+	obj := objectSet.ClientObject()
+	metrics.ObjectSetPhase.SetPhase(
+		c.gvk.Kind, obj.GetNamespace(), obj.GetName(), string(objectSet.GetStatusPhase()))
+
+	if objectSet.IsArchived() {
+		c.deletePhaseMetrics(objectSet)
+		metrics.ObjectSetManagedObjects.DeleteLabelValues(c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+		return
+	}
+	var objects int32
+	for _, phaseStatus := range objectSet.GetStatusPhases() {
+		objects += phaseStatus.Objects
+	}
+	metrics.ObjectSetManagedObjects.WithLabelValues(
+		c.gvk.Kind, obj.GetNamespace(), obj.GetName()).Set(float64(objects))
+}
+
+// Removes all series of the ObjectSet.
+func (c *GenericObjectSetController) deleteMetrics(objectSet genericObjectSet) {
+	obj := objectSet.ClientObject()
+	metrics.ObjectSetPhase.DeleteObject(c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+	metrics.ObjectSetManagedObjects.DeleteLabelValues(c.gvk.Kind, obj.GetNamespace(), obj.GetName())
+	c.deletePhaseMetrics(objectSet)
+}
+
+func (c *GenericObjectSetController) deletePhaseMetrics(objectSet genericObjectSet) {
+	obj := objectSet.ClientObject()
+	phases := objectSet.GetPhases()
+	names := make([]string, len(phases))
+	for i := range phases {
+		names[i] = phases[i].Name
+	}
+	metrics.DeletePhaseMetrics(c.gvk.Kind, obj.GetNamespace(), obj.GetName(), names...)
+}
+
 func (c *GenericObjectSetController) newOperand() genericObjectSet {
 	obj, err := c.scheme.New(c.gvk)
 	if err != nil {
@@ -185,16 +241,27 @@ func (c *GenericObjectSetController) newPhase() genericObjectSetPhase {
 func (c *GenericObjectSetController) handleDeletion(
 	ctx context.Context, objectSet genericObjectSet,
 ) error {
-	done, err := c.teardownHandler.Teardown(ctx, objectSet)
+	result, err := c.teardownHandler.Teardown(ctx, objectSet)
 	if err != nil {
 		return fmt.Errorf("error tearing down during deletion: %w", err)
 	}
 
-	if !done {
+	if !result.Done {
+		if len(result.Terminating) > 0 {
+			controllers.LoggerFromContext(ctx).Info(
+				"waiting for objects to terminate", "objects", result.Message())
+		}
 		// dont remove finalizers before deletion is done
 		return nil
 	}
 
+	// slices are only needed until all objects are torn down.
+	if err := packages.ReleaseSlices(
+		ctx, c.client, objectSet.ClientObject(), objectSet.GetPhases()); err != nil {
+		return err
+	}
+
+	c.deleteMetrics(objectSet)
 	return controllers.HandleCommonDeletion(ctx, objectSet.ClientObject(), c.client, c.dw, packages.CacheFinalizer)
 }
 
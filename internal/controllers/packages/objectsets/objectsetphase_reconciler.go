@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -22,17 +26,26 @@ type ObjectSetPhaseReconciler struct {
 	client            client.Client
 	scheme            *runtime.Scheme
 	dw                dynamicObjectWatcher
+	recorder          record.EventRecorder
 	newObjectSetPhase func() genericObjectSetPhase
 	phaseReconciler   phaseReconciler
+	hookRunner        hookRunner
+}
+
+type hookRunner interface {
+	Run(
+		ctx context.Context, objectSet genericObjectSet,
+		hookType packagesv1alpha1.HookType,
+	) (hookResult, error)
 }
 
 type phaseReconciler interface {
 	Reconcile(
 		ctx context.Context,
-		owner packages.PausingClientObject,
+		owner packages.PhaseOwner,
 		phase packagesv1alpha1.ObjectPhase,
 		probe internalprobe.Interface,
-	) (failedProbes []string, err error)
+	) (packages.PhaseProbeResult, error)
 }
 
 type dynamicObjectWatcher interface {
@@ -46,33 +59,191 @@ func (r *ObjectSetPhaseReconciler) Reconcile(
 		return ctrl.Result{}, nil
 	}
 
-	probe := packages.ParseProbes(objectSet.GetReadinessProbes())
+	probe, err := packages.ParseProbes(objectSet.GetReadinessProbes())
+	if err != nil {
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetProbesInvalid,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ProbesInvalid",
+			Message:            err.Error(),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ProbesInvalid",
+			Message:            "Readiness probes are invalid.",
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		// Nothing we can do until the spec is fixed.
+		return ctrl.Result{}, nil
+	}
+	meta.RemoveStatusCondition(objectSet.GetConditions(), packagesv1alpha1.ObjectSetProbesInvalid)
+
+	if err := packages.ValidatePausedObjects(objectSet.GetPausedFor()); err != nil {
+		if !meta.IsStatusConditionTrue(*objectSet.GetConditions(), packagesv1alpha1.ObjectSetPausedForInvalid) {
+			r.recorder.Event(objectSet.ClientObject(), corev1.EventTypeWarning, "PausedForInvalid", err.Error())
+		}
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetPausedForInvalid,
+			Status:             metav1.ConditionTrue,
+			Reason:             "PausedForInvalid",
+			Message:            err.Error(),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "PausedForInvalid",
+			Message:            "Paused objects are invalid.",
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		// Nothing we can do until the spec is fixed.
+		return ctrl.Result{}, nil
+	}
+	meta.RemoveStatusCondition(objectSet.GetConditions(), packagesv1alpha1.ObjectSetPausedForInvalid)
+
+	if _, err := packages.PhaseHooks(objectSet.GetPhases()); err != nil {
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidHooks",
+			Message:            err.Error(),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		// Nothing we can do until the spec is fixed.
+		return ctrl.Result{}, nil
+	}
+	preRolloutHook := packagesv1alpha1.HookPreInstall
+	if isUpgrade(objectSet) {
+		preRolloutHook = packagesv1alpha1.HookPreUpgrade
+	}
+	if done, err := r.runHooks(ctx, objectSet, preRolloutHook); err != nil || !done {
+		return ctrl.Result{}, err
+	}
+
 	phases := objectSet.GetPhases()
-	for _, phase := range phases {
-		var (
-			failedProbes []string
-			err          error
-		)
-		if len(phase.Class) > 0 {
-			failedProbes, err = r.reconcileRemotePhase(
-				ctx, objectSet, phase)
-		} else {
-			failedProbes, err = r.reconcileLocalPhase(
-				ctx, objectSet, phase, probe)
+	deps, err := packages.PhaseDependencies(phases)
+	if err != nil {
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidPhaseDependencies",
+			Message:            err.Error(),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		// Nothing we can do until the spec is fixed.
+		return ctrl.Result{}, nil
+	}
+
+	var (
+		now           = metav1.Now()
+		phaseStatuses = make([]packagesv1alpha1.ObjectPhaseStatus, len(phases))
+		reached       = make([]bool, len(phases))
+		// phases that passed their probes for minReadySeconds
+		passed = map[string]bool{}
+		// results of phases reconciled in-process
+		localResults []packages.PhaseProbeResult
+
+		res              ctrl.Result
+		reason           string
+		failureMessages  []string
+		minReadyMessages []string
+	)
+	for {
+		wave := nextPhaseWave(phases, deps, reached, passed)
+		if len(wave) == 0 {
+			break
+		}
+		outcomes, err := r.reconcilePhases(ctx, objectSet, wave, probe)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		for j, i := range wave {
+			phase, outcome := phases[i], outcomes[j]
+			reached[i] = true
+			if len(phase.Class) == 0 {
+				localResults = append(localResults, outcome.result)
+			}
+
+			phaseStatuses[i] = packages.NewPhaseStatus(
+				objectSet.GetStatusPhases(), phase.Name, len(outcome.failure) == 0, outcome.result, now)
+
+			if len(outcome.failure) > 0 {
+				phaseReason := "ProbeFailure"
+				if phase.ProgressDeadlineSeconds > 0 {
+					deadline := phaseStatuses[i].LastTransitionTime.Add(
+						time.Duration(phase.ProgressDeadlineSeconds) * time.Second)
+					if now.Time.Before(deadline) {
+						// requeue to report the deadline, if nothing else changes until then.
+						res = requeueEarliest(res, deadline.Sub(now.Time))
+					} else {
+						phaseReason = "ProgressDeadlineExceeded"
+						phaseStatuses[i].State = packagesv1alpha1.ObjectPhaseStateFailed
+					}
+				}
+				if reason != "ProgressDeadlineExceeded" {
+					reason = phaseReason
+				}
+				failureMessages = append(failureMessages,
+					fmt.Sprintf("Phase %q failed: %s", phase.Name, outcome.failure))
+				continue
+			}
+
+			readyAt := phaseStatuses[i].LastTransitionTime.Add(
+				time.Duration(phase.MinReadySeconds) * time.Second)
+			if now.Time.Before(readyAt) {
+				// Probes need to pass continuously for minReadySeconds.
+				phaseStatuses[i].State = packagesv1alpha1.ObjectPhaseStateReconciling
+				minReadyMessages = append(minReadyMessages, fmt.Sprintf(
+					"Phase %q needs to pass probes for %ds.", phase.Name, phase.MinReadySeconds))
+				res = requeueEarliest(res, readyAt.Sub(now.Time))
+				continue
+			}
+			passed[phase.Name] = true
+		}
+	}
+
+	for i, phase := range phases {
+		if reached[i] {
+			continue
 		}
+		phase, err := packages.ResolveSlices(ctx, r.client, objectSet.ClientObject(), phase)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+		phaseStatuses[i] = packages.NewPendingPhaseStatus(
+			phase, blockingPhases(deps[phase.Name], passed))
+	}
+	r.recordPhaseTransitions(objectSet, phaseStatuses)
+	objectSet.SetStatusPhases(phaseStatuses)
+	reportLocalPhaseResults(objectSet, localResults, now)
 
-		if len(failedProbes) > 0 {
-			meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
-				Type:               packagesv1alpha1.ObjectSetAvailable,
-				Status:             metav1.ConditionFalse,
-				Reason:             "ProbeFailure",
-				Message:            fmt.Sprintf("Phase %q failed: %s", phase.Name, strings.Join(failedProbes, ", ")),
-				ObservedGeneration: objectSet.ClientObject().GetGeneration(),
-			})
-			return ctrl.Result{}, nil
+	if len(failureMessages) > 0 {
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            strings.Join(failureMessages, "; "),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		return res, nil
+	}
+	if len(minReadyMessages) > 0 {
+		meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+			Type:               packagesv1alpha1.ObjectSetAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             "MinReadySeconds",
+			Message:            strings.Join(minReadyMessages, " "),
+			ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+		})
+		return res, nil
+	}
+
+	if !isUpgrade(objectSet) {
+		if done, err := r.runHooks(ctx, objectSet, packagesv1alpha1.HookPostInstall); err != nil || !done {
+			return ctrl.Result{}, err
 		}
 	}
 
@@ -98,15 +269,166 @@ func (r *ObjectSetPhaseReconciler) Reconcile(
 	return ctrl.Result{}, nil
 }
 
+// Runs hooks of the given type, reporting them via the Available condition until they succeeded.
+// Hook Jobs are watched, so their progress triggers the next reconcile.
+func (r *ObjectSetPhaseReconciler) runHooks(
+	ctx context.Context, objectSet genericObjectSet,
+	hookType packagesv1alpha1.HookType,
+) (done bool, err error) {
+	result, err := r.hookRunner.Run(ctx, objectSet, hookType)
+	if err != nil {
+		return false, err
+	}
+	if result.done {
+		return true, nil
+	}
+
+	reason := "HookRunning"
+	switch {
+	case result.failed:
+		reason = "HookFailed"
+	case result.conflict:
+		reason = "HookConflict"
+	}
+	meta.SetStatusCondition(objectSet.GetConditions(), metav1.Condition{
+		Type:               packagesv1alpha1.ObjectSetAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            result.message,
+		ObservedGeneration: objectSet.ClientObject().GetGeneration(),
+	})
+	return false, nil
+}
+
+// Records Events for phases that passed or failed since the last reconcile.
+func (r *ObjectSetPhaseReconciler) recordPhaseTransitions(
+	objectSet genericObjectSet, phaseStatuses []packagesv1alpha1.ObjectPhaseStatus,
+) {
+	previous := map[string]packagesv1alpha1.ObjectPhaseState{}
+	for _, phaseStatus := range objectSet.GetStatusPhases() {
+		previous[phaseStatus.Name] = phaseStatus.State
+	}
+
+	for _, phaseStatus := range phaseStatuses {
+		if previous[phaseStatus.Name] == phaseStatus.State {
+			continue
+		}
+		switch phaseStatus.State {
+		case packagesv1alpha1.ObjectPhaseStateReady:
+			r.recorder.Eventf(objectSet.ClientObject(), corev1.EventTypeNormal,
+				"PhasePassed", "Phase %q passed its probes.", phaseStatus.Name)
+		case packagesv1alpha1.ObjectPhaseStateFailed:
+			r.recorder.Eventf(objectSet.ClientObject(), corev1.EventTypeWarning,
+				"PhaseFailed", "Phase %q failed to progress.", phaseStatus.Name)
+		}
+	}
+}
+
+// Reports drift and conflicts of phases reconciled in-process.
+func reportLocalPhaseResults(
+	objectSet genericObjectSet, results []packages.PhaseProbeResult, now metav1.Time,
+) {
+	objectSet.SetStatusDrift(packages.NewDriftStatus(objectSet.GetStatusDrift(), results, now))
+	packages.SetConflictConditions(
+		objectSet.GetConditions(), objectSet.ClientObject().GetGeneration(), results)
+}
+
+// Returns the indexes of phases not reached yet,
+// whose dependencies have all passed.
+func nextPhaseWave(
+	phases []packagesv1alpha1.ObjectPhase, deps map[string][]string,
+	reached []bool, passed map[string]bool,
+) []int {
+	var wave []int
+	for i, phase := range phases {
+		if !reached[i] && len(blockingPhases(deps[phase.Name], passed)) == 0 {
+			wave = append(wave, i)
+		}
+	}
+	return wave
+}
+
+// Returns the dependencies that have not passed yet.
+func blockingPhases(deps []string, passed map[string]bool) []string {
+	var blocking []string
+	for _, dep := range deps {
+		if !passed[dep] {
+			blocking = append(blocking, dep)
+		}
+	}
+	return blocking
+}
+
+func requeueEarliest(res ctrl.Result, after time.Duration) ctrl.Result {
+	if res.RequeueAfter == 0 || after < res.RequeueAfter {
+		res.RequeueAfter = after
+	}
+	return res
+}
+
+type phaseOutcome struct {
+	result packages.PhaseProbeResult
+	// failure message, if the phase is not Available.
+	failure string
+}
+
+// Reconciles phases not depending on each other concurrently.
+// Outcomes are returned in the order of the given phase indexes.
+func (r *ObjectSetPhaseReconciler) reconcilePhases(
+	ctx context.Context,
+	objectSet genericObjectSet,
+	wave []int,
+	probe internalprobe.Interface,
+) ([]phaseOutcome, error) {
+	var (
+		phases   = objectSet.GetPhases()
+		outcomes = make([]phaseOutcome, len(wave))
+		errs     = make([]error, len(wave))
+		wg       sync.WaitGroup
+	)
+	for j, i := range wave {
+		wg.Add(1)
+		go func(j int, phase packagesv1alpha1.ObjectPhase) {
+			defer wg.Done()
+			outcomes[j], errs[j] = r.reconcilePhase(ctx, objectSet, phase, probe)
+		}(j, phases[i])
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outcomes, nil
+}
+
+func (r *ObjectSetPhaseReconciler) reconcilePhase(
+	ctx context.Context,
+	objectSet genericObjectSet,
+	phase packagesv1alpha1.ObjectPhase,
+	probe internalprobe.Interface,
+) (phaseOutcome, error) {
+	if len(phase.Class) > 0 {
+		result, failure, err := r.reconcileRemotePhase(ctx, objectSet, phase)
+		return phaseOutcome{result: result, failure: failure}, err
+	}
+
+	result, err := r.reconcileLocalPhase(ctx, objectSet, phase, probe)
+	return phaseOutcome{result: result, failure: result.Message()}, err
+}
+
 const noStatusProbeFailure = "no status reported"
 
 // Reconciles the Phase via an ObjectSetPhase object,
 // delegating the task to an auxiliary controller.
+// Returns the probe results reported by the ObjectSetPhase
+// and a failure message if the phase is not Available.
 func (r *ObjectSetPhaseReconciler) reconcileRemotePhase(
 	ctx context.Context,
 	objectSet genericObjectSet,
 	phase packagesv1alpha1.ObjectPhase,
-) (failedProbes []string, err error) {
+) (result packages.PhaseProbeResult, failure string, err error) {
 	os := objectSet.ClientObject()
 	newObjectSetPhase := r.newObjectSetPhase()
 	new := newObjectSetPhase.ClientObject()
@@ -117,30 +439,55 @@ func (r *ObjectSetPhaseReconciler) reconcileRemotePhase(
 
 	newObjectSetPhase.SetPhase(phase)
 	newObjectSetPhase.SetReadinessProbes(objectSet.GetReadinessProbes())
+	newObjectSetPhase.SetApplyMode(objectSet.GetApplyMode())
+	newObjectSetPhase.SetDriftPolicy(objectSet.GetDriftPolicy())
+	newObjectSetPhase.SetAdoptionPolicy(objectSet.GetAdoptionPolicy())
 
 	if err := controllerutil.SetControllerReference(
 		os, new, r.scheme); err != nil {
-		return nil, err
+		return result, "", err
 	}
 
+	// phases stored in slices have no inline objects.
+	resolvedPhase, err := packages.ResolveSlices(ctx, r.client, os, phase)
+	if err != nil {
+		return result, "", err
+	}
+	noStatusResult := packages.PhaseProbeResult{Objects: int32(len(resolvedPhase.Objects))}
 	existingObjectSetPhase := r.newObjectSetPhase()
 	if err := r.client.Get(
 		ctx, client.ObjectKeyFromObject(new),
 		existingObjectSetPhase.ClientObject(),
 	); err != nil && !errors.IsNotFound(err) {
-		return nil,
+		return result, "",
 			fmt.Errorf("getting existing ObjectSetPhase: %w", err)
 	} else if errors.IsNotFound(err) {
 		if err := r.client.Create(
 			ctx, newObjectSetPhase.ClientObject()); err != nil {
-			return nil,
+			return result, "",
 				fmt.Errorf("creating new ObjectSetPhase: %w", err)
 		}
 		// wait for requeue
-		return nil, nil
+		return noStatusResult, noStatusProbeFailure, nil
 	}
 
 	// ObjectSetPhase already exists
+	// -> propagate policy changes of the ObjectSet
+	if existingObjectSetPhase.GetApplyMode() != objectSet.GetApplyMode() ||
+		existingObjectSetPhase.GetDriftPolicy() != objectSet.GetDriftPolicy() ||
+		existingObjectSetPhase.GetAdoptionPolicy() != objectSet.GetAdoptionPolicy() {
+		existingObjectSetPhase.SetApplyMode(objectSet.GetApplyMode())
+		existingObjectSetPhase.SetDriftPolicy(objectSet.GetDriftPolicy())
+		existingObjectSetPhase.SetAdoptionPolicy(objectSet.GetAdoptionPolicy())
+		if err := r.client.Update(
+			ctx, existingObjectSetPhase.ClientObject()); err != nil {
+			return result, "",
+				fmt.Errorf("updating ObjectSetPhase: %w", err)
+		}
+		// wait for the status of the new generation
+		return noStatusResult, noStatusProbeFailure, nil
+	}
+
 	// -> check status
 	availableCond := meta.FindStatusCondition(
 		existingObjectSetPhase.GetConditions(),
@@ -150,19 +497,27 @@ func (r *ObjectSetPhaseReconciler) reconcileRemotePhase(
 		availableCond.ObservedGeneration !=
 			existingObjectSetPhase.ClientObject().GetGeneration() {
 		// no status reported, wait longer
-		return []string{
-			noStatusProbeFailure,
-		}, nil
+		return noStatusResult, noStatusProbeFailure, nil
+	}
+
+	result = noStatusResult
+	for _, phaseStatus := range existingObjectSetPhase.GetStatusPhases() {
+		if phaseStatus.Name == phase.Name {
+			result = packages.PhaseProbeResult{
+				Objects:       phaseStatus.Objects,
+				ReadyObjects:  phaseStatus.ReadyObjects,
+				FailedObjects: phaseStatus.FailedObjects,
+				Conflicts:     phaseStatus.Conflicts,
+			}
+		}
 	}
 	if availableCond.Status == metav1.ConditionTrue {
 		// Remote Phase is Available!
-		return nil, nil
+		return result, "", nil
 	}
 
 	// Remote Phase is not Available!
-	return []string{
-		availableCond.Message,
-	}, nil
+	return result, availableCond.Message, nil
 }
 
 // Reconciles the Phase directly in-process
@@ -171,6 +526,11 @@ func (r *ObjectSetPhaseReconciler) reconcileLocalPhase(
 	objectSet genericObjectSet,
 	phase packagesv1alpha1.ObjectPhase,
 	probe internalprobe.Interface,
-) ([]string, error) {
+) (packages.PhaseProbeResult, error) {
+	// Hooks are run separately.
+	phase, err := packages.WithoutHooks(phase)
+	if err != nil {
+		return packages.PhaseProbeResult{}, err
+	}
 	return r.phaseReconciler.Reconcile(ctx, objectSet, phase, probe)
 }
@@ -0,0 +1,280 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
+	internalprobe "github.com/thetechnick/package-operator/internal/probe"
+	"github.com/thetechnick/package-operator/internal/testutil"
+)
+
+type phaseReconcilerMock struct {
+	mock.Mock
+}
+
+func (m *phaseReconcilerMock) Reconcile(
+	ctx context.Context, owner packages.PhaseOwner,
+	phase packagesv1alpha1.ObjectPhase, probe internalprobe.Interface,
+) (packages.PhaseProbeResult, error) {
+	args := m.Called(ctx, owner, phase, probe)
+	return args.Get(0).(packages.PhaseProbeResult), args.Error(1)
+}
+
+type hookRunnerMock struct {
+	mock.Mock
+}
+
+func (m *hookRunnerMock) Run(
+	ctx context.Context, objectSet genericObjectSet, hookType packagesv1alpha1.HookType,
+) (hookResult, error) {
+	args := m.Called(ctx, objectSet, hookType)
+	return args.Get(0).(hookResult), args.Error(1)
+}
+
+func TestObjectSetPhaseReconciler_minReadyAndProgressDeadline(t *testing.T) {
+	ready := packages.PhaseProbeResult{Objects: 1, ReadyObjects: 1}
+	failing := packages.PhaseProbeResult{
+		Objects: 1,
+		FailedObjects: []packagesv1alpha1.ObjectPhaseFailedObject{{
+			Kind: "ConfigMap", Name: "cm", Message: "not ready",
+		}},
+	}
+
+	tests := []struct {
+		name string
+		// previous status of the phase, if any.
+		previousReady bool
+		previousSince time.Duration
+		result        packages.PhaseProbeResult
+
+		expectedState        packagesv1alpha1.ObjectPhaseState
+		expectedAvailable    metav1.ConditionStatus
+		expectedReason       string
+		expectedRequeueAfter time.Duration
+		// true if LastTransitionTime is carried over from the previous status.
+		expectedKeepsTransition bool
+	}{
+		{
+			name:                 "not ready yet",
+			result:               failing,
+			expectedState:        packagesv1alpha1.ObjectPhaseStateReconciling,
+			expectedAvailable:    metav1.ConditionFalse,
+			expectedReason:       "ProbeFailure",
+			expectedRequeueAfter: 60 * time.Second,
+		},
+		{
+			name:                    "ready but under minReady",
+			previousReady:           true,
+			previousSince:           10 * time.Second,
+			result:                  ready,
+			expectedState:           packagesv1alpha1.ObjectPhaseStateReconciling,
+			expectedAvailable:       metav1.ConditionFalse,
+			expectedReason:          "MinReadySeconds",
+			expectedRequeueAfter:    20 * time.Second,
+			expectedKeepsTransition: true,
+		},
+		{
+			name:                    "ready past minReady",
+			previousReady:           true,
+			previousSince:           time.Minute,
+			result:                  ready,
+			expectedState:           packagesv1alpha1.ObjectPhaseStateReady,
+			expectedAvailable:       metav1.ConditionTrue,
+			expectedReason:          "Available",
+			expectedKeepsTransition: true,
+		},
+		{
+			name:                    "deadline exceeded",
+			previousSince:           2 * time.Minute,
+			result:                  failing,
+			expectedState:           packagesv1alpha1.ObjectPhaseStateFailed,
+			expectedAvailable:       metav1.ConditionFalse,
+			expectedReason:          "ProgressDeadlineExceeded",
+			expectedKeepsTransition: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pr := &phaseReconcilerMock{}
+			pr.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return(test.result, nil)
+			hr := &hookRunnerMock{}
+			hr.On("Run", mock.Anything, mock.Anything, mock.Anything).
+				Return(hookResult{done: true}, nil)
+			r := &ObjectSetPhaseReconciler{
+				recorder:        record.NewFakeRecorder(10),
+				phaseReconciler: pr,
+				hookRunner:      hr,
+			}
+
+			objectSet := &GenericObjectSet{}
+			objectSet.Spec.Phases = []packagesv1alpha1.ObjectPhase{{
+				Name:                    "test",
+				MinReadySeconds:         30,
+				ProgressDeadlineSeconds: 60,
+			}}
+			var previousTransition metav1.Time
+			if test.previousSince > 0 {
+				previousTransition = metav1.NewTime(time.Now().Add(-test.previousSince))
+				objectSet.Status.Phases = []packagesv1alpha1.ObjectPhaseStatus{{
+					Name:               "test",
+					State:              packagesv1alpha1.ObjectPhaseStateReconciling,
+					Ready:              test.previousReady,
+					LastTransitionTime: &previousTransition,
+				}}
+			}
+
+			res, err := r.Reconcile(context.Background(), objectSet)
+			require.NoError(t, err)
+
+			// RequeueAfter is computed from the current time.
+			assert.InDelta(t, test.expectedRequeueAfter, res.RequeueAfter, float64(time.Second))
+
+			if assert.Len(t, objectSet.Status.Phases, 1) {
+				phaseStatus := objectSet.Status.Phases[0]
+				assert.Equal(t, test.expectedState, phaseStatus.State)
+				if test.expectedKeepsTransition {
+					assert.Equal(t, previousTransition, *phaseStatus.LastTransitionTime)
+				}
+			}
+
+			availableCond := meta.FindStatusCondition(
+				objectSet.Status.Conditions, packagesv1alpha1.ObjectSetAvailable)
+			if assert.NotNil(t, availableCond) {
+				assert.Equal(t, test.expectedAvailable, availableCond.Status)
+				assert.Equal(t, test.expectedReason, availableCond.Reason)
+			}
+		})
+	}
+}
+
+func TestObjectSetPhaseReconciler_reconcileRemotePhase_updatesPolicies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			existing := args.Get(2).(*packagesv1alpha1.ObjectSetPhase)
+			existing.Name = "test-remote"
+			existing.Spec.ApplyMode = packagesv1alpha1.ObjectSetApplyModeMergePatch
+			existing.Spec.DriftPolicy = packagesv1alpha1.ObjectSetDriftPolicyCorrect
+			existing.Spec.AdoptionPolicy = packagesv1alpha1.ObjectSetAdoptionPolicyIfNoController
+		}).
+		Return(nil)
+	c.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	r := &ObjectSetPhaseReconciler{
+		client: c,
+		scheme: scheme,
+		newObjectSetPhase: func() genericObjectSetPhase {
+			return &GenericObjectSetPhase{}
+		},
+	}
+
+	objectSet := &GenericObjectSet{}
+	objectSet.Name = "test"
+	objectSet.Spec.ApplyMode = packagesv1alpha1.ObjectSetApplyModeServerSideApply
+	objectSet.Spec.DriftPolicy = packagesv1alpha1.ObjectSetDriftPolicyReport
+	objectSet.Spec.AdoptionPolicy = packagesv1alpha1.ObjectSetAdoptionPolicyNever
+
+	_, failure, err := r.reconcileRemotePhase(
+		context.Background(), objectSet,
+		packagesv1alpha1.ObjectPhase{Name: "remote", Class: "remote"})
+	require.NoError(t, err)
+	assert.Equal(t, noStatusProbeFailure, failure)
+
+	c.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(obj client.Object) bool {
+		phase := obj.(*packagesv1alpha1.ObjectSetPhase)
+		return phase.Spec.ApplyMode == packagesv1alpha1.ObjectSetApplyModeServerSideApply &&
+			phase.Spec.DriftPolicy == packagesv1alpha1.ObjectSetDriftPolicyReport &&
+			phase.Spec.AdoptionPolicy == packagesv1alpha1.ObjectSetAdoptionPolicyNever
+	}), mock.Anything)
+}
+
+func TestObjectSetPhaseReconciler_pendingSlicedPhase(t *testing.T) {
+	pr := &phaseReconcilerMock{}
+	pr.On("Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(packages.PhaseProbeResult{Objects: 1}, nil)
+	hr := &hookRunnerMock{}
+	hr.On("Run", mock.Anything, mock.Anything, mock.Anything).
+		Return(hookResult{done: true}, nil)
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			slice := args.Get(2).(*packagesv1alpha1.ObjectSetSlice)
+			slice.Objects = make([]packagesv1alpha1.ObjectSetObject, 2)
+		}).
+		Return(nil)
+	r := &ObjectSetPhaseReconciler{
+		client:          c,
+		recorder:        record.NewFakeRecorder(10),
+		phaseReconciler: pr,
+		hookRunner:      hr,
+	}
+
+	objectSet := &GenericObjectSet{}
+	objectSet.Namespace = "test"
+	objectSet.Spec.Phases = []packagesv1alpha1.ObjectPhase{
+		{Name: "first", MinReadySeconds: 30},
+		{Name: "second", Slices: []string{"test-second"}},
+	}
+
+	_, err := r.Reconcile(context.Background(), objectSet)
+	require.NoError(t, err)
+
+	if assert.Len(t, objectSet.Status.Phases, 2) {
+		phaseStatus := objectSet.Status.Phases[1]
+		assert.Equal(t, packagesv1alpha1.ObjectPhaseStatePending, phaseStatus.State)
+		assert.Equal(t, int32(2), phaseStatus.Objects)
+	}
+	pr.AssertNumberOfCalls(t, "Reconcile", 1)
+}
+
+func TestObjectSetPhaseReconciler_invalidPausedFor(t *testing.T) {
+	pr := &phaseReconcilerMock{}
+	recorder := record.NewFakeRecorder(10)
+	r := &ObjectSetPhaseReconciler{
+		recorder:        recorder,
+		phaseReconciler: pr,
+	}
+
+	objectSet := &GenericObjectSet{}
+	objectSet.Spec.Phases = []packagesv1alpha1.ObjectPhase{{Name: "test"}}
+	objectSet.Spec.PausedFor = []packagesv1alpha1.ObjectSetPausedObject{{
+		Kind: "ConfigMap",
+		LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key: "app", Operator: "Bogus",
+		}}},
+	}}
+
+	for i := 0; i < 2; i++ {
+		res, err := r.Reconcile(context.Background(), objectSet)
+		require.NoError(t, err)
+		assert.True(t, res.IsZero())
+	}
+
+	assert.True(t, meta.IsStatusConditionTrue(
+		objectSet.Status.Conditions, packagesv1alpha1.ObjectSetPausedForInvalid))
+	availableCond := meta.FindStatusCondition(
+		objectSet.Status.Conditions, packagesv1alpha1.ObjectSetAvailable)
+	if assert.NotNil(t, availableCond) {
+		assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+		assert.Equal(t, "PausedForInvalid", availableCond.Reason)
+	}
+	// reported once, not on every reconcile.
+	assert.Len(t, recorder.Events, 1)
+	pr.AssertNotCalled(t, "Reconcile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
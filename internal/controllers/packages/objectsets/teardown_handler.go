@@ -3,73 +3,198 @@ package objectsets
 import (
 	"context"
 	"fmt"
+	"time"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers"
 	"github.com/thetechnick/package-operator/internal/controllers/packages"
+	"github.com/thetechnick/package-operator/internal/ownerhandling"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 type TeardownHandler struct {
 	client            client.Client
+	scheme            *runtime.Scheme
 	dw                dynamicWatchFreer
+	hookRunner        hookRunner
 	newObjectSetPhase func() genericObjectSetPhase
 }
 
 func NewTeardownHandler(
 	c client.Client,
+	scheme *runtime.Scheme,
 	dw dynamicWatchFreer,
+	hookRunner hookRunner,
 	newObjectSetPhase func() genericObjectSetPhase,
 ) *TeardownHandler {
 	return &TeardownHandler{
 		client:            c,
+		scheme:            scheme,
 		dw:                dw,
+		hookRunner:        hookRunner,
 		newObjectSetPhase: newObjectSetPhase,
 	}
 }
 
+// Tears down all phases of the ObjectSet.
+// The result reports objects of all attempted phases, that are still terminating.
 func (h *TeardownHandler) Teardown(
 	ctx context.Context, objectSet genericObjectSet,
-) (done bool, err error) {
+) (result packages.TeardownResult, err error) {
 	log := controllers.LoggerFromContext(ctx)
 
+	if runsPreDeleteHooks(objectSet) {
+		if done, err := h.runPreDeleteHooks(ctx, objectSet); err != nil || !done {
+			return result, err
+		}
+	}
+
 	phases := objectSet.GetPhases()
-	reverse(phases) // teardown in reverse order
+	dependents := phaseDependents(phases)
 
 	// "scale to zero"
+	// Phases are torn down in reverse dependency order,
+	// waiting for all phases that depend on them first.
+	var (
+		attempted = map[string]bool{}
+		tornDown  = map[string]bool{}
+	)
+	for {
+		var wave []packagesv1alpha1.ObjectPhase
+		for i := len(phases) - 1; i >= 0; i-- {
+			phase := phases[i]
+			if !attempted[phase.Name] && allTornDown(dependents[phase.Name], tornDown) {
+				wave = append(wave, phase)
+			}
+		}
+		if len(wave) == 0 {
+			break
+		}
+
+		for _, phase := range wave {
+			attempted[phase.Name] = true
+			log.Info("cleanup", "phase", phase.Name)
+			phaseResult, err := h.teardownPhase(ctx, objectSet, phase)
+			if err != nil {
+				return result, fmt.Errorf("error archiving phase: %w", err)
+			}
+			result.Merge(phaseResult)
+			if phaseResult.Done {
+				tornDown[phase.Name] = true
+			}
+		}
+	}
+
+	for _, phase := range phases {
+		if !tornDown[phase.Name] {
+			return result, nil
+		}
+	}
+	result.Done = true
+	return result, nil
+}
+
+// Pre-delete hooks only run when a live ObjectSet is deleted, e.g. with its Package.
+// Archived revisions, like those pruned from the revision history, don't run them.
+// Orphaned ObjectSets hand their objects over, so pre-delete hooks don't apply either.
+func runsPreDeleteHooks(objectSet genericObjectSet) bool {
+	if objectSet.ClientObject().GetDeletionTimestamp().IsZero() ||
+		objectSet.IsArchived() {
+		return false
+	}
+	// phases were already torn down, while the ObjectSet was archived before.
+	return !meta.IsStatusConditionTrue(
+		*objectSet.GetConditions(), packagesv1alpha1.ObjectSetArchived)
+}
+
+// Runs pre-delete hooks before any object is removed.
+// A failed hook blocks deletion, until its Job is deleted to retry it.
+func (h *TeardownHandler) runPreDeleteHooks(
+	ctx context.Context, objectSet genericObjectSet,
+) (done bool, err error) {
+	log := controllers.LoggerFromContext(ctx)
+
+	if _, err := packages.PhaseHooks(objectSet.GetPhases()); err != nil {
+		// Hooks never ran, don't block deletion.
+		log.Info("skipping invalid pre-delete hooks", "err", err.Error())
+		return true, nil
+	}
+
+	result, err := h.hookRunner.Run(ctx, objectSet, packagesv1alpha1.HookPreDelete)
+	if err != nil {
+		return false, fmt.Errorf("running pre-delete hooks: %w", err)
+	}
+	if !result.done {
+		log.Info("waiting for pre-delete hooks", "message", result.message)
+	}
+	return result.done, nil
+}
+
+// Returns the names of the phases depending on each phase.
+// Falls back to the order of phases, if dependencies are invalid,
+// so the teardown of a broken ObjectSet can still complete.
+func phaseDependents(phases []packagesv1alpha1.ObjectPhase) map[string][]string {
+	deps, err := packages.PhaseDependencies(phases)
+	if err != nil {
+		deps = map[string][]string{}
+		for i := 1; i < len(phases); i++ {
+			deps[phases[i].Name] = []string{phases[i-1].Name}
+		}
+	}
+
+	dependents := map[string][]string{}
 	for _, phase := range phases {
-		log.Info("cleanup", "phase", phase.Name)
-		if cleanupDone, err := h.teardownPhase(ctx, objectSet, phase); err != nil {
-			return false, fmt.Errorf("error archiving phase: %w", err)
-		} else if !cleanupDone {
-			return false, nil
+		for _, dep := range deps[phase.Name] {
+			dependents[dep] = append(dependents[dep], phase.Name)
 		}
 	}
+	return dependents
+}
 
-	return true, nil
+func allTornDown(phaseNames []string, tornDown map[string]bool) bool {
+	for _, name := range phaseNames {
+		if !tornDown[name] {
+			return false
+		}
+	}
+	return true
 }
 
 func (h *TeardownHandler) teardownPhase(
 	ctx context.Context,
 	objectSet genericObjectSet,
 	phase packagesv1alpha1.ObjectPhase,
-) (cleanupDone bool, err error) {
+) (packages.TeardownResult, error) {
 	if len(phase.Class) > 0 {
 		return h.teardownRemotePhase(ctx, objectSet, phase)
 	}
-	return packages.TeardownPhase(ctx, h.client, objectSet, phase)
+	if objectSet.IsOrphaned() {
+		return packages.OrphanPhase(ctx, h.client, h.client, ownerhandling.Native, objectSet, phase)
+	}
+	// pre-delete hooks are garbage collected with the ObjectSet,
+	// to not run them again during deletion.
+	phase, err := packages.WithoutHooks(phase, packagesv1alpha1.HookPreDelete)
+	if err != nil {
+		return packages.TeardownResult{}, err
+	}
+	return packages.TeardownPhase(ctx, h.client, h.client, ownerhandling.Native, objectSet, phase)
 }
 
 func (h *TeardownHandler) teardownRemotePhase(
 	ctx context.Context,
 	objectSet genericObjectSet,
 	phase packagesv1alpha1.ObjectPhase,
-) (cleanupDone bool, err error) {
+) (result packages.TeardownResult, err error) {
 	log := controllers.LoggerFromContext(ctx)
 
-	defer log.Info("teardown of remote phase", "phase", phase.Name, "cleanupDone", cleanupDone)
+	defer func() {
+		log.Info("teardown of remote phase", "phase", phase.Name, "cleanupDone", result.Done)
+	}()
 	objectSetPhase := h.newObjectSetPhase()
 	err = h.client.Get(ctx, client.ObjectKey{
 		Name:      objectSet.ClientObject().GetName() + "-" + phase.Name,
@@ -77,10 +202,16 @@ func (h *TeardownHandler) teardownRemotePhase(
 	}, objectSetPhase.ClientObject())
 	if err != nil && errors.IsNotFound(err) {
 		// object is already gone -> nothing to cleanup
-		return true, nil
+		result.Done = true
+		return result, nil
 	}
 	if err != nil {
-		return false, err
+		return result, err
+	}
+
+	if objectSetPhase.ClientObject().GetDeletionTimestamp() != nil {
+		// ObjectSetPhase is tearing down its objects, waiting on its finalizers.
+		return h.terminatingObjectSetPhase(objectSetPhase, phase)
 	}
 
 	// ensure PausedObject is up-to-date, _before_ we delete
@@ -91,26 +222,48 @@ func (h *TeardownHandler) teardownRemotePhase(
 		// needs update/more wait time for ack
 		objectSetPhase.SetSpecPausedFor(objectSet.GetPausedFor())
 		if err := h.client.Update(ctx, objectSetPhase.ClientObject()); err != nil {
-			return false, fmt.Errorf("updating ObjectSetPhase before archival: %w", err)
+			return result, fmt.Errorf("updating ObjectSetPhase before archival: %w", err)
+		}
+		return result, nil
+	}
+
+	// ensure the ObjectSetPhase releases its objects instead of deleting them
+	if objectSet.IsOrphaned() && !objectSetPhase.IsSpecOrphaned() {
+		objectSetPhase.SetSpecOrphaned(true)
+		if err := h.client.Update(ctx, objectSetPhase.ClientObject()); err != nil {
+			return result, fmt.Errorf("orphaning ObjectSetPhase before archival: %w", err)
 		}
-		return false, nil
 	}
 
 	err = h.client.Delete(ctx, objectSetPhase.ClientObject())
 	if err != nil && errors.IsNotFound(err) {
-		return true, nil
+		result.Done = true
+		return result, nil
 	}
 	if err != nil {
-		return false, fmt.Errorf("deleting ObjectSetPhase for archival: %w", err)
+		return result, fmt.Errorf("deleting ObjectSetPhase for archival: %w", err)
 	}
 	// ObjectSetPhase is not confirmed to be gone
 	// wait an extra turn.
-	return false, nil
+	return result, nil
 }
 
-// reverse the order of a slice
-func reverse[T any](s []T) {
-	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
-		s[i], s[j] = s[j], s[i]
+// Reports a deleted ObjectSetPhase as terminating object,
+// as its objects are torn down by another controller.
+func (h *TeardownHandler) terminatingObjectSetPhase(
+	objectSetPhase genericObjectSetPhase, phase packagesv1alpha1.ObjectPhase,
+) (packages.TeardownResult, error) {
+	obj := objectSetPhase.ClientObject()
+	gvk, err := apiutil.GVKForObject(obj, h.scheme)
+	if err != nil {
+		return packages.TeardownResult{}, err
 	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	terminating, remaining := packages.NewTerminatingObject(
+		obj, time.Duration(phase.TeardownTimeoutSeconds)*time.Second, time.Now())
+	return packages.TeardownResult{
+		Terminating:  []packages.TerminatingObject{terminating},
+		RequeueAfter: remaining,
+	}, nil
 }
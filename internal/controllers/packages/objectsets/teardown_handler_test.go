@@ -0,0 +1,64 @@
+package objectsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestTeardownHandler_preDeleteHooks(t *testing.T) {
+	tests := []struct {
+		name           string
+		deleted        bool
+		lifecycleState packagesv1alpha1.ObjectSetLifecycleState
+		archivedCond   bool
+		runsHooks      bool
+	}{
+		{name: "deleted", deleted: true, runsHooks: true},
+		{name: "not deleted"},
+		{
+			name: "pruned archived revision", deleted: true,
+			lifecycleState: packagesv1alpha1.ObjectSetLifecycleStateArchived,
+		},
+		{
+			name: "orphaned", deleted: true,
+			lifecycleState: packagesv1alpha1.ObjectSetLifecycleStateOrphaned,
+		},
+		{name: "already torn down", deleted: true, archivedCond: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hr := &hookRunnerMock{}
+			hr.On("Run", mock.Anything, mock.Anything, packagesv1alpha1.HookPreDelete).
+				Return(hookResult{done: true}, nil)
+			h := NewTeardownHandler(nil, nil, nil, hr, nil)
+
+			objectSet := &GenericObjectSet{}
+			objectSet.Spec.LifecycleState = test.lifecycleState
+			if test.deleted {
+				now := metav1.Now()
+				objectSet.DeletionTimestamp = &now
+			}
+			if test.archivedCond {
+				objectSet.Status.Conditions = []metav1.Condition{{
+					Type: packagesv1alpha1.ObjectSetArchived, Status: metav1.ConditionTrue,
+				}}
+			}
+
+			result, err := h.Teardown(context.Background(), objectSet)
+			require.NoError(t, err)
+			assert.True(t, result.Done)
+			if test.runsHooks {
+				hr.AssertCalled(t, "Run", mock.Anything, mock.Anything, packagesv1alpha1.HookPreDelete)
+			} else {
+				hr.AssertNotCalled(t, "Run", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
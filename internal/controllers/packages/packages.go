@@ -5,8 +5,11 @@ import (
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	internalprobe "github.com/thetechnick/package-operator/internal/probe"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
@@ -16,52 +19,160 @@ const (
 	ObjectSetLabelKey = "packages.thetechnick.ninja/object-set"
 )
 
+// Parses the given ObjectSetProbes into a single probe.
+// Returns an aggregate of all validation errors encountered.
 func ParseProbes(
 	packageProbes []packagesv1alpha1.ObjectSetProbe,
-) internalprobe.Interface {
-	var probes internalprobe.ProbeList
-	for _, packageProbe := range packageProbes {
-		probe := internalprobe.Parse(packageProbe.Probes)
-
-		// wrap filter type
-		selector := packageProbe.Selector
-		switch selector.Type {
-		case packagesv1alpha1.ProbeSelectorKind:
-			if selector.Kind == nil {
-				continue
-			}
-
-			probe = &internalprobe.KindSelector{
-				Interface: probe,
-				GroupKind: schema.GroupKind{
-					Group: selector.Kind.Group,
-					Kind:  selector.Kind.Kind,
-				},
-			}
-
-		default:
-			// Unknown selector type
+) (internalprobe.Interface, error) {
+	var (
+		probes internalprobe.ProbeList
+		errs   []error
+	)
+	for i, packageProbe := range packageProbes {
+		probe, err := parseProbe(packageProbe)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("readinessProbes[%d]: %w", i, err))
 			continue
 		}
-
 		probes = append(probes, probe)
 	}
-	return probes
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
+	return probes, nil
 }
 
+func parseProbe(
+	packageProbe packagesv1alpha1.ObjectSetProbe,
+) (internalprobe.Interface, error) {
+	probe, err := internalprobe.Parse(packageProbe.Probes)
+	if err != nil {
+		return nil, err
+	}
+
+	// wrap filter type
+	selector := packageProbe.Selector
+	switch selector.Type {
+	case packagesv1alpha1.ProbeSelectorKind:
+		if selector.Kind == nil {
+			return nil, missingSelectorSpecError(selector.Type, "kind")
+		}
+
+		return &internalprobe.KindSelector{
+			Interface: probe,
+			GroupKind: schema.GroupKind{
+				Group: selector.Kind.Group,
+				Kind:  selector.Kind.Kind,
+			},
+		}, nil
+
+	case packagesv1alpha1.ProbeSelectorLabel:
+		if selector.Label == nil {
+			return nil, missingSelectorSpecError(selector.Type, "label")
+		}
+
+		labelSelector, err := metav1.LabelSelectorAsSelector(&selector.Label.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+		return withOptionalKindSelector(&internalprobe.LabelSelector{
+			Interface: probe,
+			Selector:  labelSelector,
+		}, selector.Label.Group, selector.Label.Kind)
+
+	case packagesv1alpha1.ProbeSelectorName:
+		if selector.Name == nil {
+			return nil, missingSelectorSpecError(selector.Type, "name")
+		}
+
+		nameSelector, err := internalprobe.NewNameSelector(
+			probe, selector.Name.Namespace, selector.Name.Name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name selector: %w", err)
+		}
+		return withOptionalKindSelector(
+			nameSelector, selector.Name.Group, selector.Name.Kind)
+
+	case packagesv1alpha1.ProbeSelectorDefault:
+		if len(packageProbe.Probes) > 0 {
+			return nil, fmt.Errorf("selector of type %q does not accept probes", selector.Type)
+		}
+
+		return &internalprobe.DefaultProbe{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown selector type %q", selector.Type)
+}
+
+func missingSelectorSpecError(selectorType packagesv1alpha1.ProbeSelectorType, field string) error {
+	return fmt.Errorf("selector of type %q requires .selector.%s to be set", selectorType, field)
+}
+
+// Wraps the given probe into a KindSelector, if a Kind is specified.
+// A Group without Kind is rejected, instead of probing objects of all groups.
+func withOptionalKindSelector(
+	probe internalprobe.Interface, group, kind string,
+) (internalprobe.Interface, error) {
+	if len(kind) == 0 {
+		if len(group) > 0 {
+			return nil, fmt.Errorf("group %q requires a kind to be set", group)
+		}
+		return probe, nil
+	}
+	return &internalprobe.KindSelector{
+		Interface: probe,
+		GroupKind: schema.GroupKind{
+			Group: group,
+			Kind:  kind,
+		},
+	}, nil
+}
+
+// Returns true if the object is selected by the paused object.
+// Empty name and namespace match any, invalid label selectors match nothing
+// and are reported via ValidatePausedObjects.
 func PausedObjectMatches(ppo packagesv1alpha1.ObjectSetPausedObject, obj client.Object) bool {
 	gvk := obj.GetObjectKind().GroupVersionKind()
-	if gvk.Group == ppo.Group &&
-		gvk.Kind == ppo.Kind &&
-		obj.GetName() == ppo.Name {
+	if gvk.Group != ppo.Group || gvk.Kind != ppo.Kind {
+		return false
+	}
+	if len(ppo.Name) > 0 && obj.GetName() != ppo.Name {
+		return false
+	}
+	if len(ppo.Namespace) > 0 && obj.GetNamespace() != ppo.Namespace {
+		return false
+	}
+	if ppo.LabelSelector == nil {
 		return true
 	}
-	return false
+	selector, err := metav1.LabelSelectorAsSelector(ppo.LabelSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// Checks the label selectors of the given paused objects.
+func ValidatePausedObjects(pausedFor []packagesv1alpha1.ObjectSetPausedObject) error {
+	for i, ppo := range pausedFor {
+		if ppo.LabelSelector == nil {
+			continue
+		}
+		if _, err := metav1.LabelSelectorAsSelector(ppo.LabelSelector); err != nil {
+			return fmt.Errorf("pausedFor[%d]: invalid label selector: %w", i, err)
+		}
+	}
+	return nil
 }
 
 func UnstructuredFromObjectObject(packageObject *packagesv1alpha1.ObjectSetObject) (*unstructured.Unstructured, error) {
+	raw, err := objectSetObjectRaw(packageObject)
+	if err != nil {
+		return nil, err
+	}
+
 	obj := &unstructured.Unstructured{}
-	if err := yaml.Unmarshal(packageObject.Object.Raw, obj); err != nil {
+	if err := yaml.Unmarshal(raw, obj); err != nil {
 		return nil, fmt.Errorf("converting RawExtension into unstructured: %w", err)
 	}
 	return obj, nil
@@ -11,6 +11,7 @@ import (
 type genericPackage interface {
 	ClientObject() client.Object
 	UpdatePhase()
+	GetStatusPhase() packagesv1alpha1.PackageStatusPhase
 	GetConditions() *[]metav1.Condition
 	GetImage() string
 	GetSource() interface{}
@@ -59,6 +60,10 @@ func (a *GenericPackage) UpdatePhase() {
 	a.Status.Phase = packagesv1alpha1.PackagePhaseNotReady
 }
 
+func (a *GenericPackage) GetStatusPhase() packagesv1alpha1.PackageStatusPhase {
+	return a.Status.Phase
+}
+
 func (a *GenericPackage) GetConditions() *[]metav1.Condition {
 	return &a.Status.Conditions
 }
@@ -115,6 +120,10 @@ func (a *GenericClusterPackage) UpdatePhase() {
 	a.Status.Phase = packagesv1alpha1.PackagePhaseNotReady
 }
 
+func (a *GenericClusterPackage) GetStatusPhase() packagesv1alpha1.PackageStatusPhase {
+	return a.Status.Phase
+}
+
 func (a *GenericClusterPackage) GetConditions() *[]metav1.Condition {
 	return &a.Status.Conditions
 }
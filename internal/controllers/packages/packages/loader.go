@@ -13,6 +13,7 @@ import (
 
 	"github.com/go-logr/logr"
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers/packages"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -109,8 +110,12 @@ func (l *packageLoader) Load() (genericObjectDeployment, error) {
 			continue
 		}
 
-		phases[i].Objects = unstructuredSliceToObjectSetObjectSlice(
+		objects, err := unstructuredSliceToObjectSetObjectSlice(
 			l.phaseObjs[phase.Name])
+		if err != nil {
+			return nil, fmt.Errorf("phase %s: %w", phase.Name, err)
+		}
+		phases[i].Objects = objects
 	}
 	for phase := range l.phaseObjs {
 		if _, ok := knownPhases[phase]; !ok {
@@ -228,14 +233,30 @@ func (l *packageLoader) loadKubernetesObjectsFromBytes(fileYaml []byte) (
 	return objects, nil
 }
 
+// Objects larger than this are stored gzip compressed.
+const compressObjectThreshold = 32 * 1024
+
 func unstructuredSliceToObjectSetObjectSlice(
-	objs []unstructured.Unstructured) (out []packagesv1alpha1.ObjectSetObject) {
+	objs []unstructured.Unstructured) (out []packagesv1alpha1.ObjectSetObject, err error) {
 	for i := range objs {
-		out = append(out, packagesv1alpha1.ObjectSetObject{
-			Object: runtime.RawExtension{
-				Object: &objs[i],
-			},
-		})
+		j, err := json.Marshal(&objs[i])
+		if err != nil {
+			return nil, fmt.Errorf("marshalling object: %w", err)
+		}
+		if len(j) <= compressObjectThreshold {
+			out = append(out, packagesv1alpha1.ObjectSetObject{
+				Object: runtime.RawExtension{
+					Object: &objs[i],
+				},
+			})
+			continue
+		}
+
+		compressed, err := packages.CompressObject(&objs[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, compressed)
 	}
 	return
 }
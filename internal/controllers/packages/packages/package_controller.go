@@ -6,13 +6,16 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/thetechnick/package-operator/internal/controllers"
+	"github.com/thetechnick/package-operator/internal/metrics"
 	"github.com/thetechnick/package-operator/internal/ownerhandling"
 	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -27,6 +30,7 @@ type GenericPackageController struct {
 	client              client.Client
 	log                 logr.Logger
 	scheme              *runtime.Scheme
+	recorder            record.EventRecorder
 	jobOwnerStrategy    ownerStrategy
 	pkoNamespace        string
 
@@ -50,12 +54,13 @@ type reconciler interface {
 
 func NewPackageController(
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, pkoNamespace string,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	pkoNamespace string,
 ) *GenericPackageController {
 	return NewGenericPackageController(
 		newPackage,
 		newObjectDeployment,
-		c, log, scheme, pkoNamespace,
+		c, log, scheme, recorder, pkoNamespace,
 		// Running all unpack-jobs within the package-operator namespace
 		// requires cross-namespace owner handling,
 		// which is not available with Native owner handling.
@@ -65,12 +70,13 @@ func NewPackageController(
 
 func NewClusterPackageController(
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, pkoNamespace string,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	pkoNamespace string,
 ) *GenericPackageController {
 	return NewGenericPackageController(
 		newClusterPackage,
 		newClusterObjectDeployment,
-		c, log, scheme, pkoNamespace,
+		c, log, scheme, recorder, pkoNamespace,
 		ownerhandling.Native,
 	)
 }
@@ -79,13 +85,15 @@ func NewGenericPackageController(
 	newPackage packageFactory,
 	newObjectDeployment objectDeploymentFactory,
 	c client.Client, log logr.Logger,
-	scheme *runtime.Scheme, pkoNamespace string,
+	scheme *runtime.Scheme, recorder record.EventRecorder,
+	pkoNamespace string,
 	jobOwnerStrategy ownerStrategy,
 ) *GenericPackageController {
 	controller := &GenericPackageController{
 		client:              c,
 		log:                 log,
 		scheme:              scheme,
+		recorder:            recorder,
 		newPackage:          newPackage,
 		newObjectDeployment: newObjectDeployment,
 		jobOwnerStrategy:    jobOwnerStrategy,
@@ -94,7 +102,7 @@ func NewGenericPackageController(
 
 	controller.reconciler = []reconciler{
 		newHashReconciler(),
-		newUnpackReconciler(c, scheme, pkoNamespace, jobOwnerStrategy),
+		newUnpackReconciler(c, scheme, recorder, pkoNamespace, jobOwnerStrategy),
 		newObjectDeploymentReconciler(c, scheme, newObjectDeployment),
 	}
 
@@ -152,9 +160,17 @@ func (c *GenericPackageController) Reconcile(
 	}
 
 	packageObj.UpdatePhase()
+	c.recordPhase(packageObj)
 	return res, c.client.Status().Update(ctx, packageObj.ClientObject())
 }
 
+func (c *GenericPackageController) recordPhase(pack genericPackage) {
+	obj := pack.ClientObject()
+	gvk, _ := apiutil.GVKForObject(obj, c.scheme)
+	metrics.PackagePhase.SetPhase(
+		gvk.Kind, obj.GetNamespace(), obj.GetName(), string(pack.GetStatusPhase()))
+}
+
 // ensures the cache finalizer is set on the given object
 func (c *GenericPackageController) ensureCacheFinalizer(
 	ctx context.Context, pack genericPackage,
@@ -177,6 +193,10 @@ func (c *GenericPackageController) handleDeletion(
 	}
 
 	obj := pack.ClientObject()
+	gvk, _ := apiutil.GVKForObject(obj, c.scheme)
+	metrics.PackagePhase.DeleteObject(gvk.Kind, obj.GetNamespace(), obj.GetName())
+	metrics.UnpackJobFailures.DeleteLabelValues(obj.GetNamespace(), obj.GetName())
+
 	if controllerutil.ContainsFinalizer(obj, jobFinalizer) {
 		controllerutil.RemoveFinalizer(obj, jobFinalizer)
 
@@ -5,12 +5,14 @@ import (
 	"fmt"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/metrics"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -18,6 +20,7 @@ import (
 type unpackReconciler struct {
 	client           client.Client
 	scheme           *runtime.Scheme
+	recorder         record.EventRecorder
 	pkoNamespace     string
 	jobOwnerStrategy ownerStrategy
 }
@@ -25,12 +28,14 @@ type unpackReconciler struct {
 func newUnpackReconciler(
 	client client.Client,
 	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
 	pkoNamespace string,
 	jobOwnerStrategy ownerStrategy,
 ) *unpackReconciler {
 	return &unpackReconciler{
 		client:           client,
 		scheme:           scheme,
+		recorder:         recorder,
 		pkoNamespace:     pkoNamespace,
 		jobOwnerStrategy: jobOwnerStrategy,
 	}
@@ -55,6 +60,11 @@ func (c *unpackReconciler) ensureUnpack(
 		if cond.Type == batchv1.JobComplete &&
 			cond.Status == corev1.ConditionTrue {
 			jobCompleted = true
+			if !meta.IsStatusConditionTrue(
+				*packageObj.GetConditions(), packagesv1alpha1.PackageUnpacked) {
+				// Only observe once, completed Jobs are kept around.
+				observeUnpackDuration(job)
+			}
 			meta.SetStatusCondition(
 				packageObj.GetConditions(), metav1.Condition{
 					Type:               packagesv1alpha1.PackageUnpacked,
@@ -77,6 +87,12 @@ func (c *unpackReconciler) ensureUnpack(
 					Message:            "Unpack job failed",
 					ObservedGeneration: packageObj.ClientObject().GetGeneration(),
 				})
+			c.recorder.Eventf(packageObj.ClientObject(), corev1.EventTypeWarning, "UnpackFailed",
+				"Unpack job %s/%s failed: %s", job.Namespace, job.Name, cond.Message)
+			c.recorder.Eventf(job, corev1.EventTypeWarning, "UnpackFailed",
+				"Unpacking %s failed: %s", packageObj.ClientObject().GetName(), cond.Message)
+			metrics.UnpackJobFailures.WithLabelValues(
+				packageObj.ClientObject().GetNamespace(), packageObj.ClientObject().GetName()).Inc()
 			if err := c.client.Delete(ctx, job); err != nil {
 				return fmt.Errorf("deleting failed job: %w", err)
 			}
@@ -96,6 +112,14 @@ func (c *unpackReconciler) ensureUnpack(
 	return nil
 }
 
+func observeUnpackDuration(job *batchv1.Job) {
+	if job.Status.StartTime == nil || job.Status.CompletionTime == nil {
+		return
+	}
+	metrics.UnpackJobDuration.Observe(
+		job.Status.CompletionTime.Sub(job.Status.StartTime.Time).Seconds())
+}
+
 const packageSourceHashAnnotation = "packages.thetechnick.ninja/package-source-hash"
 
 func (c *unpackReconciler) ensureUnpackJob(
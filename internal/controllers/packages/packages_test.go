@@ -0,0 +1,205 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestParseProbes_selectors(t *testing.T) {
+	probes := []packagesv1alpha1.Probe{{
+		Type:      packagesv1alpha1.ProbeCondition,
+		Condition: &packagesv1alpha1.ProbeConditionSpec{Type: "Available", Status: "True"},
+	}}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("Deployment")
+	obj.SetName("web")
+	obj.SetNamespace("prod")
+	obj.SetLabels(map[string]string{"app": "web"})
+
+	tests := []struct {
+		name     string
+		selector packagesv1alpha1.ProbeSelector
+		probed   bool
+		err      string
+	}{
+		{
+			name: "label",
+			selector: packagesv1alpha1.ProbeSelector{
+				Type: packagesv1alpha1.ProbeSelectorLabel,
+				Label: &packagesv1alpha1.PackageProbeLabelSpec{
+					Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			},
+			probed: true,
+		},
+		{
+			name: "label of other kind",
+			selector: packagesv1alpha1.ProbeSelector{
+				Type: packagesv1alpha1.ProbeSelectorLabel,
+				Label: &packagesv1alpha1.PackageProbeLabelSpec{
+					Group:    "apps",
+					Kind:     "StatefulSet",
+					Selector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			},
+			probed: false,
+		},
+		{
+			name: "name of kind",
+			selector: packagesv1alpha1.ProbeSelector{
+				Type: packagesv1alpha1.ProbeSelectorName,
+				Name: &packagesv1alpha1.PackageProbeNameSpec{
+					Group: "apps", Kind: "Deployment", Namespace: "prod", Name: "w*",
+				},
+			},
+			probed: true,
+		},
+		{
+			name: "name in other namespace",
+			selector: packagesv1alpha1.ProbeSelector{
+				Type: packagesv1alpha1.ProbeSelectorName,
+				Name: &packagesv1alpha1.PackageProbeNameSpec{Namespace: "dev", Name: "web"},
+			},
+			probed: false,
+		},
+		{
+			name:     "missing label spec",
+			selector: packagesv1alpha1.ProbeSelector{Type: packagesv1alpha1.ProbeSelectorLabel},
+			err:      `readinessProbes[0]: selector of type "Label" requires .selector.label to be set`,
+		},
+		{
+			name: "invalid name pattern",
+			selector: packagesv1alpha1.ProbeSelector{
+				Type: packagesv1alpha1.ProbeSelectorName,
+				Name: &packagesv1alpha1.PackageProbeNameSpec{Name: "web-["},
+			},
+			err: `readinessProbes[0]: invalid name selector: invalid pattern "web-["`,
+		},
+		{
+			name: "group without kind",
+			selector: packagesv1alpha1.ProbeSelector{
+				Type: packagesv1alpha1.ProbeSelectorName,
+				Name: &packagesv1alpha1.PackageProbeNameSpec{Group: "apps", Name: "web"},
+			},
+			err: `readinessProbes[0]: group "apps" requires a kind to be set`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			probe, err := ParseProbes([]packagesv1alpha1.ObjectSetProbe{{
+				Selector: test.selector, Probes: probes,
+			}})
+			if len(test.err) > 0 {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// The object has no conditions, so the probe fails if executed.
+			success, _ := probe.Probe(obj)
+			assert.Equal(t, !test.probed, success)
+		})
+	}
+}
+
+func TestPausedObjectMatches(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetName("cm")
+	obj.SetNamespace("a")
+	obj.SetLabels(map[string]string{"app": "test"})
+
+	tests := []struct {
+		name    string
+		paused  packagesv1alpha1.ObjectSetPausedObject
+		matches bool
+	}{
+		{
+			name:    "name in any namespace",
+			paused:  packagesv1alpha1.ObjectSetPausedObject{Kind: "ConfigMap", Name: "cm"},
+			matches: true,
+		},
+		{
+			name:    "name in namespace",
+			paused:  packagesv1alpha1.ObjectSetPausedObject{Kind: "ConfigMap", Name: "cm", Namespace: "a"},
+			matches: true,
+		},
+		{
+			name:    "name in other namespace",
+			paused:  packagesv1alpha1.ObjectSetPausedObject{Kind: "ConfigMap", Name: "cm", Namespace: "b"},
+			matches: false,
+		},
+		{
+			name:    "other kind",
+			paused:  packagesv1alpha1.ObjectSetPausedObject{Kind: "Secret", Name: "cm"},
+			matches: false,
+		},
+		{
+			name: "label selector",
+			paused: packagesv1alpha1.ObjectSetPausedObject{
+				Kind: "ConfigMap", Namespace: "a",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+			},
+			matches: true,
+		},
+		{
+			name: "label selector not matching",
+			paused: packagesv1alpha1.ObjectSetPausedObject{
+				Kind:          "ConfigMap",
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}},
+			},
+			matches: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.matches, PausedObjectMatches(test.paused, obj))
+		})
+	}
+}
+
+func TestPausedObjectMatches_clusterScoped(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("rbac.authorization.k8s.io/v1")
+	obj.SetKind("ClusterRole")
+	obj.SetName("role")
+
+	assert.True(t, PausedObjectMatches(packagesv1alpha1.ObjectSetPausedObject{
+		Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "role",
+	}, obj))
+	// paused objects with a namespace never match cluster-scoped objects.
+	assert.False(t, PausedObjectMatches(packagesv1alpha1.ObjectSetPausedObject{
+		Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "role", Namespace: "a",
+	}, obj))
+}
+
+func TestValidatePausedObjects(t *testing.T) {
+	valid := packagesv1alpha1.ObjectSetPausedObject{
+		Kind:          "ConfigMap",
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+	}
+	invalid := packagesv1alpha1.ObjectSetPausedObject{
+		Kind: "ConfigMap",
+		LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key: "app", Operator: "Bogus",
+		}}},
+	}
+	assert.NoError(t, ValidatePausedObjects(
+		[]packagesv1alpha1.ObjectSetPausedObject{valid, {Kind: "Secret", Name: "s"}}))
+
+	err := ValidatePausedObjects([]packagesv1alpha1.ObjectSetPausedObject{valid, invalid})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "pausedFor[1]: invalid label selector")
+	}
+}
@@ -0,0 +1,91 @@
+package packages
+
+import (
+	"fmt"
+	"strings"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+// Returns the names of the phases each phase depends on.
+// If no phase declares dependsOn, every phase depends on the phase before it,
+// so phases are reconciled strictly in order.
+func PhaseDependencies(phases []packagesv1alpha1.ObjectPhase) (map[string][]string, error) {
+	deps := make(map[string][]string, len(phases))
+	linear := true
+	for _, phase := range phases {
+		if _, ok := deps[phase.Name]; ok {
+			return nil, fmt.Errorf("duplicate phase %q", phase.Name)
+		}
+		deps[phase.Name] = nil
+		if len(phase.DependsOn) > 0 {
+			linear = false
+		}
+	}
+
+	for i, phase := range phases {
+		if linear {
+			if i > 0 {
+				deps[phase.Name] = []string{phases[i-1].Name}
+			}
+			continue
+		}
+
+		for _, dep := range phase.DependsOn {
+			if _, ok := deps[dep]; !ok {
+				return nil, fmt.Errorf("phase %q depends on unknown phase %q", phase.Name, dep)
+			}
+		}
+		deps[phase.Name] = phase.DependsOn
+	}
+
+	if cycle := findPhaseCycle(phases, deps); len(cycle) > 0 {
+		return nil, fmt.Errorf("phase dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return deps, nil
+}
+
+// Returns the first dependency cycle found, starting and ending with the same phase.
+func findPhaseCycle(
+	phases []packagesv1alpha1.ObjectPhase, deps map[string][]string,
+) []string {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var (
+		state = map[string]int{}
+		path  []string
+		visit func(name string) []string
+	)
+	visit = func(name string) []string {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i := range path {
+				if path[i] == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, phase := range phases {
+		if cycle := visit(phase.Name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package packages
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestPhaseDependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		phases       []packagesv1alpha1.ObjectPhase
+		expectedDeps map[string][]string
+		expectedErr  string
+	}{
+		{
+			name: "linear",
+			phases: []packagesv1alpha1.ObjectPhase{
+				{Name: "a"}, {Name: "b"}, {Name: "c"},
+			},
+			expectedDeps: map[string][]string{
+				"a": nil, "b": {"a"}, "c": {"b"},
+			},
+		},
+		{
+			name: "graph",
+			phases: []packagesv1alpha1.ObjectPhase{
+				{Name: "rbac"}, {Name: "deploy"},
+				{Name: "post", DependsOn: []string{"rbac", "deploy"}},
+			},
+			expectedDeps: map[string][]string{
+				"rbac": nil, "deploy": nil, "post": {"rbac", "deploy"},
+			},
+		},
+		{
+			name: "unknown phase",
+			phases: []packagesv1alpha1.ObjectPhase{
+				{Name: "a", DependsOn: []string{"x"}},
+			},
+			expectedErr: `phase "a" depends on unknown phase "x"`,
+		},
+		{
+			name: "duplicate phase",
+			phases: []packagesv1alpha1.ObjectPhase{
+				{Name: "a"}, {Name: "a"},
+			},
+			expectedErr: `duplicate phase "a"`,
+		},
+		{
+			name: "cycle",
+			phases: []packagesv1alpha1.ObjectPhase{
+				{Name: "a"},
+				{Name: "b", DependsOn: []string{"a", "c"}},
+				{Name: "c", DependsOn: []string{"b"}},
+			},
+			expectedErr: "phase dependency cycle: b -> c -> b",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deps, err := PhaseDependencies(test.phases)
+			if len(test.expectedErr) > 0 {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedDeps, deps)
+		})
+	}
+}
@@ -2,47 +2,81 @@ package packages
 
 import (
 	"context"
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 	"github.com/thetechnick/package-operator/internal/controllers"
+	"github.com/thetechnick/package-operator/internal/metrics"
 	internalprobe "github.com/thetechnick/package-operator/internal/probe"
 )
 
+// Field manager used for server-side apply.
+const FieldOwner = "package-operator"
+
 type PhaseReconciler struct {
-	dw     dynamicWatcher
-	client client.Client
-	scheme *runtime.Scheme
+	dw       dynamicWatcher
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	// Records events on managed objects,
+	// which may live in another cluster than their owner.
+	objectRecorder record.EventRecorder
+	// Reads slices and other owners, which are stored next to the owner,
+	// independent of the cluster objects are reconciled in.
+	sliceReader client.Reader
 
 	ownerStrategy ownerStrategy
+	// Apply mode used, if the owner does not specify one.
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode
+	// Number of objects reconciled concurrently, if the phase does not specify it.
+	defaultConcurrency int
 }
 
 func NewPhaseReconciler(
 	dw dynamicWatcher,
 	c client.Client,
+	sliceReader client.Reader,
 	scheme *runtime.Scheme,
+	recorder record.EventRecorder,
+	objectRecorder record.EventRecorder,
 	ownerStrategy ownerStrategy,
+	defaultApplyMode packagesv1alpha1.ObjectSetApplyMode,
+	defaultConcurrency int,
 ) *PhaseReconciler {
 	return &PhaseReconciler{
-		dw:            dw,
-		client:        c,
-		scheme:        scheme,
-		ownerStrategy: ownerStrategy,
+		dw:                 dw,
+		client:             c,
+		sliceReader:        sliceReader,
+		scheme:             scheme,
+		recorder:           recorder,
+		objectRecorder:     objectRecorder,
+		ownerStrategy:      ownerStrategy,
+		defaultApplyMode:   defaultApplyMode,
+		defaultConcurrency: defaultConcurrency,
 	}
 }
 
 type ownerStrategy interface {
 	IsOwner(owner, obj metav1.Object) bool
 	ReleaseController(obj metav1.Object)
+	GetController(obj metav1.Object) (metav1.OwnerReference, bool)
+	RemoveOwner(owner, obj metav1.Object)
 	SetControllerReference(owner, obj metav1.Object, scheme *runtime.Scheme) error
 }
 
@@ -55,37 +89,189 @@ type PausingClientObject interface {
 	IsObjectPaused(obj client.Object) bool
 }
 
+type PhaseOwner interface {
+	PausingClientObject
+	GetApplyMode() packagesv1alpha1.ObjectSetApplyMode
+	GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy
+	GetStatusDrift() *packagesv1alpha1.ObjectSetDriftStatus
+	GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy
+}
+
+// Returned when server-side apply conflicts with other field managers.
+type fieldConflictError struct {
+	err error
+}
+
+func (e *fieldConflictError) Error() string {
+	return e.err.Error()
+}
+
+// Returned when the adoption policy prevents taking over a pre-existing object.
+type adoptionConflictError struct {
+	reason string
+}
+
+func (e *adoptionConflictError) Error() string {
+	return "adoption conflict: " + e.reason
+}
+
+// Returned when an update changes immutable fields
+// and recreating the object is not enabled.
+type immutableFieldConflictError struct {
+	fields []string
+}
+
+func (e *immutableFieldConflictError) Error() string {
+	if len(e.fields) == 0 {
+		return "immutable fields changed"
+	}
+	return "immutable fields changed: " + strings.Join(e.fields, ", ")
+}
+
 func (r *PhaseReconciler) Reconcile(
 	ctx context.Context,
-	owner PausingClientObject,
+	owner PhaseOwner,
 	phase packagesv1alpha1.ObjectPhase,
 	probe internalprobe.Interface,
-) (failedProbes []string, err error) {
+) (result PhaseProbeResult, err error) {
+	start := time.Now()
 
-	// Reconcile objects in phase
-	for _, phaseObject := range phase.Objects {
-		obj, err := unstructuredFromObjectObject(&phaseObject)
+	phase, err = ResolveSlices(ctx, r.sliceReader, owner.ClientObject(), phase)
+	if err != nil {
+		return PhaseProbeResult{}, err
+	}
+
+	objects := make([]*unstructured.Unstructured, len(phase.Objects))
+	for i := range phase.Objects {
+		obj, err := UnstructuredFromObjectObject(&phase.Objects[i])
 		if err != nil {
-			return nil, err
+			return PhaseProbeResult{}, err
 		}
-		if err := r.reconcileObject(ctx, owner, obj); err != nil {
-			return nil, err
+		objects[i] = obj
+	}
+
+	// Reconcile objects in phase
+	outcomes := r.reconcileObjects(ctx, owner, phase, objects)
+
+	// Results are collected in object order,
+	// to report failures independent of the order objects finished in.
+	var probeFailures int
+	for i, obj := range objects {
+		result.Objects++
+
+		var (
+			conflictErr          *fieldConflictError
+			adoptionConflictErr  *adoptionConflictError
+			immutableConflictErr *immutableFieldConflictError
+		)
+		drift, err := outcomes[i].drift, outcomes[i].err
+		switch {
+		case goerrors.As(err, &adoptionConflictErr):
+			// Object is not under our control, so there is nothing to probe.
+			failed := newFailedObject(obj, adoptionConflictErr.Error())
+			result.AdoptionConflicts = append(result.AdoptionConflicts, failed)
+			result.FailedObjects = append(result.FailedObjects, failed)
+			continue
+		case goerrors.As(err, &immutableConflictErr):
+			// Object is stuck on the previous revision, probing it is meaningless.
+			failed := newFailedObject(obj, immutableConflictErr.Error())
+			result.ImmutableFieldConflicts = append(result.ImmutableFieldConflicts, failed)
+			result.FailedObjects = append(result.FailedObjects, failed)
+			continue
+		case goerrors.As(err, &conflictErr):
+			result.Conflicts = append(result.Conflicts, newFailedObject(obj, conflictErr.Error()))
+		case err != nil:
+			return PhaseProbeResult{}, err
+		}
+		if drift != nil {
+			r.recordDrift(owner, obj, drift, &result)
 		}
 
 		if success, message := probe.Probe(obj); !success {
-			gvk := obj.GroupVersionKind()
-			failedProbes = append(failedProbes,
-				fmt.Sprintf("%s %s %s/%s: %s", gvk.Group, gvk.Kind, obj.GetNamespace(), obj.GetName(), message))
+			result.FailedObjects = append(result.FailedObjects, newFailedObject(obj, message))
+			probeFailures++
+			continue
 		}
+		result.ReadyObjects++
 	}
-	return failedProbes, nil
+	r.recordPhaseMetrics(owner, phase.Name, time.Since(start), probeFailures)
+	return result, nil
+}
+
+// Outcome of reconciling a single object.
+type objectOutcome struct {
+	drift *objectDrift
+	err   error
+}
+
+// Reconciles the given objects with bounded concurrency.
+// Outcomes are returned in the order of the given objects.
+// No new objects are started after the first error.
+func (r *PhaseReconciler) reconcileObjects(
+	ctx context.Context,
+	owner PhaseOwner,
+	phase packagesv1alpha1.ObjectPhase,
+	objects []*unstructured.Unstructured,
+) []objectOutcome {
+	concurrency := r.defaultConcurrency
+	if phase.MaxConcurrency > 0 {
+		concurrency = int(phase.MaxConcurrency)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		outcomes = make([]objectOutcome, len(objects))
+		wg       sync.WaitGroup
+		workers  = make(chan struct{}, concurrency)
+		failed   int32
+	)
+	for i := range objects {
+		workers <- struct{}{}
+		if atomic.LoadInt32(&failed) > 0 {
+			// Objects after a failed object are never looked at.
+			<-workers
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			drift, err := r.reconcileObject(ctx, owner, phase, objects[i])
+			outcomes[i] = objectOutcome{drift: drift, err: err}
+			if isFatalObjectError(err) {
+				atomic.StoreInt32(&failed, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return outcomes
+}
+
+// Returns true for errors that abort reconciliation of the phase,
+// instead of being reported for the object.
+func isFatalObjectError(err error) bool {
+	var (
+		conflictErr          *fieldConflictError
+		adoptionConflictErr  *adoptionConflictError
+		immutableConflictErr *immutableFieldConflictError
+	)
+	return err != nil &&
+		!goerrors.As(err, &conflictErr) &&
+		!goerrors.As(err, &adoptionConflictErr) &&
+		!goerrors.As(err, &immutableConflictErr)
 }
 
 func (r *PhaseReconciler) reconcileObject(
 	ctx context.Context,
-	owner PausingClientObject,
+	owner PhaseOwner,
+	phase packagesv1alpha1.ObjectPhase,
 	obj *unstructured.Unstructured,
-) error {
+) (drift *objectDrift, err error) {
 	log := controllers.LoggerFromContext(ctx)
 
 	// Add our own label
@@ -105,31 +291,42 @@ func (r *PhaseReconciler) reconcileObject(
 	// Ensure we are owner
 	if err := r.ownerStrategy.SetControllerReference(owner.ClientObject(), obj, r.scheme); err != nil {
 		// if err := controllerutil.SetControllerReference(owner.ClientObject(), obj, r.scheme); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Ensure to watchlist
 	if err := r.dw.Watch(owner.ClientObject(), obj); err != nil {
-		return fmt.Errorf("watching new resource: %w", err)
+		return nil, fmt.Errorf("watching new resource: %w", err)
 	}
 
 	currentObj := obj.DeepCopy()
-	err := r.client.Get(ctx, client.ObjectKeyFromObject(obj), currentObj)
-	if err != nil && !errors.IsNotFound(err) {
-		return fmt.Errorf("getting %s: %w", obj.GroupVersionKind(), err)
+	getErr := r.client.Get(ctx, client.ObjectKeyFromObject(obj), currentObj)
+	if getErr != nil && !errors.IsNotFound(getErr) {
+		return nil, fmt.Errorf("getting %s: %w", obj.GroupVersionKind(), getErr)
 	}
 
 	if owner.IsObjectPaused(obj) {
 		// Paused, don't reconcile.
 		// Just report the latest object state.
 		*obj = *currentObj
-		return nil
+		return nil, nil
 	}
 
-	if errors.IsNotFound(err) {
-		err := r.client.Create(ctx, obj)
+	applyMode := owner.GetApplyMode()
+	if len(applyMode) == 0 {
+		applyMode = r.defaultApplyMode
+	}
+	reconcileMode, err := getReconcileMode(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	exists := !errors.IsNotFound(getErr)
+	if !exists &&
+		applyMode != packagesv1alpha1.ObjectSetApplyModeServerSideApply {
+		err := r.client.Create(ctx, obj, client.FieldOwner(FieldOwner))
 		if err != nil {
-			return fmt.Errorf("creating: %w", err)
+			return nil, fmt.Errorf("creating: %w", err)
 		}
 	}
 
@@ -145,6 +342,11 @@ func (r *PhaseReconciler) reconcileObject(
 	// r.ownerStrategy.SetOwnerReferences(obj, newOwnerRefs)
 
 	if !isOwner {
+		if err := r.checkAdoption(ctx, owner, obj, currentObj); err != nil {
+			*obj = *currentObj
+			return nil, err
+		}
+
 		// Release other controllers
 
 		// Just patch the OwnerReferences of the object,
@@ -154,40 +356,550 @@ func (r *PhaseReconciler) reconcileObject(
 		r.ownerStrategy.ReleaseController(currentObj)
 
 		if err := r.ownerStrategy.SetControllerReference(owner.ClientObject(), updatedOwnersObj, r.scheme); err != nil {
-			return err
+			return nil, err
 		}
 
 		log.Info("patching for ownership", "obj", client.ObjectKeyFromObject(obj))
 		if err := r.client.Patch(
 			ctx, currentObj, client.MergeFrom(updatedOwnersObj)); err != nil {
-			return fmt.Errorf("patching Owners: %w", err)
+			return nil, fmt.Errorf("patching Owners: %w", err)
+		}
+		r.recordObjectEvent(owner, obj, "Adopted", "adopted")
+	}
+
+	if exists && reconcileMode == packagesv1alpha1.ReconcileModeCreateOnly {
+		// Never touch the object again after creation.
+		*obj = *currentObj
+		return nil, nil
+	}
+
+	var changed bool
+	if exists {
+		changed = r.isChanged(ctx, applyMode, obj, currentObj)
+	}
+
+	// Objects already under our control, that no longer match the desired state,
+	// have been changed by someone else.
+	if changed && isOwner {
+		drift = &objectDrift{manager: lastFieldManager(currentObj)}
+
+		switch owner.GetDriftPolicy() {
+		case packagesv1alpha1.ObjectSetDriftPolicyReport:
+			*obj = *currentObj
+			return drift, nil
+		case packagesv1alpha1.ObjectSetDriftPolicyIgnore:
+			*obj = *currentObj
+			return nil, nil
 		}
+		log.Info("correcting drift", "obj", client.ObjectKeyFromObject(obj), "manager", drift.manager)
+	}
+
+	if changed && reconcileMode == packagesv1alpha1.ReconcileModeReplace {
+		log.Info("replacing", "obj", client.ObjectKeyFromObject(obj))
+		return drift, r.replace(ctx, obj, currentObj)
 	}
 
-	// Update
-	if !equality.Semantic.DeepDerivative(obj.Object, currentObj.Object) {
+	if applyMode == packagesv1alpha1.ObjectSetApplyModeServerSideApply {
+		if exists {
+			if err := r.migrateUpdateManagers(ctx, currentObj); err != nil {
+				return drift, err
+			}
+		}
+		err := r.apply(ctx, obj, currentObj, forceOwnership(owner, isOwner))
+		if fields, ok := immutableFieldChanges(err); ok {
+			return drift, r.recreate(ctx, phase, obj, currentObj, fields)
+		}
+		if err == nil && exists &&
+			obj.GetResourceVersion() != currentObj.GetResourceVersion() {
+			r.recordObjectEvent(owner, obj, "Patched", "patched")
+		}
+		return drift, err
+	}
+
+	// Update, objects were just created otherwise.
+	if changed {
 		log.Info("patching spec", "obj", client.ObjectKeyFromObject(obj))
 		// this is only updating "known" fields,
 		// so annotations/labels and other properties will be preserved.
 		err := r.client.Patch(
-			ctx, obj, client.MergeFrom(&unstructured.Unstructured{}))
+			ctx, obj, client.MergeFrom(&unstructured.Unstructured{}), client.FieldOwner(FieldOwner))
 
 		// Alternative to override the object completely:
 		// err := r.Update(ctx, obj)
+		if fields, ok := immutableFieldChanges(err); ok {
+			return drift, r.recreate(ctx, phase, obj, currentObj, fields)
+		}
 		if err != nil {
-			return fmt.Errorf("patching spec: %w", err)
+			return nil, fmt.Errorf("patching spec: %w", err)
 		}
+		r.recordObjectEvent(owner, obj, "Patched", "patched")
+	} else if exists {
+		*obj = *currentObj
+	}
+
+	return drift, nil
+}
+
+// Reports whether the existing object differs from the desired state.
+// The API server normalizes some values, e.g. quantities or defaulted fields,
+// so objects not matching outright are compared to the result of a dry-run write.
+func (r *PhaseReconciler) isChanged(
+	ctx context.Context, applyMode packagesv1alpha1.ObjectSetApplyMode,
+	obj, currentObj *unstructured.Unstructured,
+) bool {
+	if equality.Semantic.DeepDerivative(obj.Object, currentObj.Object) {
+		return false
+	}
+
+	dryRunObj := obj.DeepCopy()
+	var err error
+	if applyMode == packagesv1alpha1.ObjectSetApplyModeServerSideApply {
+		err = r.client.Patch(ctx, dryRunObj, client.Apply,
+			client.FieldOwner(FieldOwner), client.ForceOwnership, client.DryRunAll)
 	} else {
+		err = r.client.Patch(ctx, dryRunObj, client.MergeFrom(&unstructured.Unstructured{}),
+			client.FieldOwner(FieldOwner), client.DryRunAll)
+	}
+	if err != nil {
+		// e.g. changes to immutable fields, which are handled by the actual write.
+		return true
+	}
+	return !equality.Semantic.DeepEqual(
+		withoutWriteMetadata(dryRunObj), withoutWriteMetadata(currentObj))
+}
+
+// Strips metadata changed by every write.
+func withoutWriteMetadata(obj *unstructured.Unstructured) map[string]interface{} {
+	obj = obj.DeepCopy()
+	unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "generation")
+	return obj.Object
+}
+
+// Checks if the adoption policy allows to take over the pre-existing object.
+// Returns an adoptionConflictError if not.
+func (r *PhaseReconciler) checkAdoption(
+	ctx context.Context,
+	owner PhaseOwner,
+	obj, currentObj *unstructured.Unstructured,
+) error {
+	controller, hasController := r.ownerStrategy.GetController(currentObj)
+	if hasController {
+		isSibling, err := IsSiblingOwner(ctx, r.sliceReader, r.scheme, owner.ClientObject(), controller)
+		if err != nil {
+			return fmt.Errorf("checking controller of pre-existing object: %w", err)
+		}
+		if isSibling {
+			// Object is managed by another revision and can be handed over.
+			return nil
+		}
+	}
+
+	policy := owner.GetAdoptionPolicy()
+	if override, ok := obj.GetAnnotations()[packagesv1alpha1.AdoptionPolicyAnnotation]; ok {
+		policy = packagesv1alpha1.ObjectSetAdoptionPolicy(override)
+	}
+
+	switch policy {
+	case packagesv1alpha1.ObjectSetAdoptionPolicyAlways:
+		return nil
+
+	case packagesv1alpha1.ObjectSetAdoptionPolicyIfNoController, "":
+		if !hasController {
+			return nil
+		}
+
+	case packagesv1alpha1.ObjectSetAdoptionPolicyNever:
+		if !hasController {
+			return &adoptionConflictError{
+				reason: "pre-existing object is not managed by package-operator",
+			}
+		}
+
+	default:
+		return fmt.Errorf("invalid adoption policy %q", policy)
+	}
+
+	return &adoptionConflictError{
+		reason: fmt.Sprintf("controlled by %s %s %q",
+			controller.APIVersion, controller.Kind, controller.Name),
+	}
+}
+
+// Deletes the object, so it's recreated with the desired state on the next reconcile.
+func (r *PhaseReconciler) replace(
+	ctx context.Context,
+	obj, currentObj *unstructured.Unstructured,
+	opts ...client.DeleteOption,
+) error {
+	uid := currentObj.GetUID()
+	opts = append([]client.DeleteOption{client.Preconditions{UID: &uid}}, opts...)
+	if err := r.client.Delete(
+		ctx, currentObj, opts...,
+	); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("deleting for replacement: %w", err)
+	}
+	*obj = *currentObj
+	return nil
+}
+
+// Recreates an object with changed immutable fields, if enabled for the phase or object.
+// Returns an immutableFieldConflictError otherwise.
+func (r *PhaseReconciler) recreate(
+	ctx context.Context,
+	phase packagesv1alpha1.ObjectPhase,
+	obj, currentObj *unstructured.Unstructured,
+	fields []string,
+) error {
+	propagation, enabled, err := getRecreatePropagationPolicy(phase, obj)
+	if err != nil {
+		return err
+	}
+	if !enabled {
 		*obj = *currentObj
+		return &immutableFieldConflictError{fields: fields}
+	}
+
+	log := controllers.LoggerFromContext(ctx)
+	log.Info("recreating for immutable field change",
+		"obj", client.ObjectKeyFromObject(obj), "fields", fields)
+	return r.replace(ctx, obj, currentObj, client.PropagationPolicy(propagation))
+}
+
+// Error messages of the API server, when an update changes immutable fields.
+var immutableFieldMessages = []string{
+	"field is immutable",
+	// StatefulSets only allow updates to some of their spec fields.
+	"updates to statefulset spec for fields other than",
+}
+
+// Checks if the given error was caused by changes to immutable fields.
+// Returns the paths of the offending fields, if reported by the API server.
+func immutableFieldChanges(err error) (fields []string, ok bool) {
+	if !errors.IsInvalid(err) {
+		return nil, false
 	}
 
+	var status errors.APIStatus
+	if goerrors.As(err, &status) && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if isImmutableFieldMessage(cause.Message) {
+				fields = append(fields, cause.Field)
+			}
+		}
+	}
+	return fields, len(fields) > 0 || isImmutableFieldMessage(err.Error())
+}
+
+func isImmutableFieldMessage(msg string) bool {
+	for _, immutableMsg := range immutableFieldMessages {
+		if strings.Contains(msg, immutableMsg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Ownership is forced when taking over the object from another owner,
+// and when correcting drift, to take back fields changed by other field managers.
+func forceOwnership(owner PhaseOwner, isOwner bool) bool {
+	switch owner.GetDriftPolicy() {
+	case packagesv1alpha1.ObjectSetDriftPolicyReport,
+		packagesv1alpha1.ObjectSetDriftPolicyIgnore:
+		return !isOwner
+	}
+	return true
+}
+
+// Field managers of package-operator, before the FieldOwner was used for all writes.
+// Defaults to the name of the binary, when no field manager is set.
+var legacyFieldManagers = map[string]bool{
+	FieldOwner:                 true,
+	"package-operator-manager": true,
+	"package-phase-manager":    true,
+}
+
+// Moves fields managed by package-operator via Update, e.g. in MergePatch mode,
+// into the Apply entry of the FieldOwner, before the object is applied the first time.
+// Otherwise server-side apply never prunes them, when they are removed from the template.
+func (r *PhaseReconciler) migrateUpdateManagers(
+	ctx context.Context, currentObj *unstructured.Unstructured,
+) error {
+	var (
+		managedFields []metav1.ManagedFieldsEntry
+		updateEntries []metav1.ManagedFieldsEntry
+		applyIndex    = -1
+	)
+	for _, entry := range currentObj.GetManagedFields() {
+		if entry.Operation == metav1.ManagedFieldsOperationUpdate &&
+			len(entry.Subresource) == 0 && legacyFieldManagers[entry.Manager] {
+			updateEntries = append(updateEntries, entry)
+			continue
+		}
+		if entry.Operation == metav1.ManagedFieldsOperationApply &&
+			len(entry.Subresource) == 0 && entry.Manager == FieldOwner {
+			applyIndex = len(managedFields)
+		}
+		managedFields = append(managedFields, entry)
+	}
+	if len(updateEntries) == 0 {
+		return nil
+	}
+
+	if applyIndex == -1 {
+		applyEntry := updateEntries[0]
+		applyEntry.Manager = FieldOwner
+		applyEntry.Operation = metav1.ManagedFieldsOperationApply
+		applyEntry.FieldsV1 = &metav1.FieldsV1{Raw: []byte("{}")}
+		applyIndex = len(managedFields)
+		managedFields = append(managedFields, applyEntry)
+	}
+	applyEntry := &managedFields[applyIndex]
+	for _, entry := range updateEntries {
+		fields, err := mergeFieldsV1(applyEntry.FieldsV1, entry.FieldsV1)
+		if err != nil {
+			return fmt.Errorf("migrating managed fields of %q: %w", entry.Manager, err)
+		}
+		applyEntry.FieldsV1 = fields
+	}
+
+	updatedObj := currentObj.DeepCopy()
+	updatedObj.SetManagedFields(managedFields)
+	if err := r.client.Patch(ctx, updatedObj, client.MergeFromWithOptions(
+		currentObj, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("migrating managed fields: %w", err)
+	}
+	*currentObj = *updatedObj
 	return nil
 }
 
-func unstructuredFromObjectObject(packageObject *packagesv1alpha1.ObjectSetObject) (*unstructured.Unstructured, error) {
-	obj := &unstructured.Unstructured{}
-	if err := yaml.Unmarshal(packageObject.Object.Raw, obj); err != nil {
-		return nil, fmt.Errorf("converting RawExtension into unstructured: %w", err)
+// Returns the union of both field sets.
+func mergeFieldsV1(a, b *metav1.FieldsV1) (*metav1.FieldsV1, error) {
+	var aFields, bFields map[string]interface{}
+	if a != nil && len(a.Raw) > 0 {
+		if err := json.Unmarshal(a.Raw, &aFields); err != nil {
+			return nil, err
+		}
+	}
+	if b != nil && len(b.Raw) > 0 {
+		if err := json.Unmarshal(b.Raw, &bFields); err != nil {
+			return nil, err
+		}
+	}
+	raw, err := json.Marshal(mergeFieldSets(aFields, bFields))
+	if err != nil {
+		return nil, err
+	}
+	return &metav1.FieldsV1{Raw: raw}, nil
+}
+
+func mergeFieldSets(a, b map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for k, v := range a {
+		merged[k] = v
+	}
+	for k, v := range b {
+		aChild, aOK := merged[k].(map[string]interface{})
+		bChild, bOK := v.(map[string]interface{})
+		if aOK && bOK {
+			merged[k] = mergeFieldSets(aChild, bChild)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// Applies the object via server-side apply.
+// Conflicts with other field managers are returned as fieldConflictError,
+// while obj is updated to reflect the current state of the object.
+func (r *PhaseReconciler) apply(
+	ctx context.Context,
+	obj, currentObj *unstructured.Unstructured,
+	force bool,
+) error {
+	opts := []client.PatchOption{client.FieldOwner(FieldOwner)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	err := r.client.Patch(ctx, obj, client.Apply, opts...)
+	if errors.IsConflict(err) {
+		*obj = *currentObj
+		return &fieldConflictError{err: err}
+	}
+	if err != nil {
+		return fmt.Errorf("applying: %w", err)
+	}
+	return nil
+}
+
+// Reports drift as Event, metric and in the phase result.
+func (r *PhaseReconciler) recordDrift(
+	owner PhaseOwner, obj *unstructured.Unstructured,
+	drift *objectDrift, result *PhaseProbeResult,
+) {
+	gvk := obj.GroupVersionKind()
+	message := fmt.Sprintf("%s %s %s/%s was changed by %q",
+		gvk.Group, gvk.Kind, obj.GetNamespace(), obj.GetName(), drift.manager)
+
+	if owner.GetDriftPolicy() == packagesv1alpha1.ObjectSetDriftPolicyReport {
+		drifted := newFailedObject(obj, fmt.Sprintf("changed by %q", drift.manager))
+		result.DriftedObjects = append(result.DriftedObjects, drifted)
+		if isDriftReported(owner.GetStatusDrift(), drifted) {
+			// Only report drift once, it's persisting until corrected.
+			return
+		}
+		r.recorder.Event(owner.ClientObject(), corev1.EventTypeWarning, "DriftDetected", message)
+		metrics.ObjectDrift.WithLabelValues(gvk.Group, gvk.Kind, "Reported").Inc()
+		return
+	}
+
+	r.recorder.Event(owner.ClientObject(), corev1.EventTypeWarning, "DriftCorrected", message)
+	metrics.ObjectDrift.WithLabelValues(gvk.Group, gvk.Kind, "Corrected").Inc()
+	result.DriftCorrections++
+}
+
+// Reports reconcile duration and probe failures of a phase.
+func (r *PhaseReconciler) recordPhaseMetrics(
+	owner PhaseOwner, phaseName string,
+	duration time.Duration, probeFailures int,
+) {
+	ownerGVK, _ := apiutil.GVKForObject(owner.ClientObject(), r.scheme)
+	labels := []string{
+		ownerGVK.Kind, owner.ClientObject().GetNamespace(),
+		owner.ClientObject().GetName(), phaseName,
+	}
+	metrics.PhaseReconcileDuration.WithLabelValues(labels...).Observe(duration.Seconds())
+	metrics.PhaseProbeFailures.WithLabelValues(labels...).Add(float64(probeFailures))
+}
+
+// Records an Event on the owner and the managed object.
+func (r *PhaseReconciler) recordObjectEvent(
+	owner PhaseOwner, obj *unstructured.Unstructured, reason, verb string,
+) {
+	gvk := obj.GroupVersionKind()
+	r.recorder.Eventf(owner.ClientObject(), corev1.EventTypeNormal, reason,
+		"%s %s %s/%s was %s", gvk.Group, gvk.Kind, obj.GetNamespace(), obj.GetName(), verb)
+
+	ownerGVK, _ := apiutil.GVKForObject(owner.ClientObject(), r.scheme)
+	r.objectRecorder.Eventf(obj, corev1.EventTypeNormal, reason,
+		"Object was %s by %s %s", verb, ownerGVK.Kind,
+		client.ObjectKeyFromObject(owner.ClientObject()))
+}
+
+func isDriftReported(
+	drift *packagesv1alpha1.ObjectSetDriftStatus,
+	drifted packagesv1alpha1.ObjectPhaseFailedObject,
+) bool {
+	if drift == nil {
+		return false
+	}
+	for _, reported := range drift.Objects {
+		if reported == drifted {
+			return true
+		}
+	}
+	// Objects is truncated, all reported objects are tracked via their hash.
+	hash := driftedObjectHash(drifted)
+	for _, reported := range drift.ObjectHashes {
+		if reported == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func driftedObjectHash(drifted packagesv1alpha1.ObjectPhaseFailedObject) string {
+	return ComputeHash(drifted, nil)
+}
+
+// Describes a change to an object by another actor.
+type objectDrift struct {
+	// Field manager that last changed the object.
+	manager string
+}
+
+// Returns the field manager that most recently changed the object.
+func lastFieldManager(obj *unstructured.Unstructured) string {
+	var (
+		manager string
+		last    time.Time
+	)
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Time != nil && !entry.Time.Time.Before(last) {
+			manager = entry.Manager
+			last = entry.Time.Time
+		}
+	}
+	if len(manager) == 0 {
+		return "unknown"
+	}
+	return manager
+}
+
+func getReconcileMode(obj *unstructured.Unstructured) (packagesv1alpha1.ReconcileMode, error) {
+	mode := packagesv1alpha1.ReconcileMode(
+		obj.GetAnnotations()[packagesv1alpha1.ReconcileModeAnnotation])
+	switch mode {
+	case "":
+		return packagesv1alpha1.ReconcileModeUpdate, nil
+	case packagesv1alpha1.ReconcileModeCreateOnly,
+		packagesv1alpha1.ReconcileModeUpdate,
+		packagesv1alpha1.ReconcileModeReplace:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid %s annotation %q", packagesv1alpha1.ReconcileModeAnnotation, mode)
+}
+
+// Returns the propagation policy to recreate objects with,
+// and false if recreation is not enabled for the object.
+func getRecreatePropagationPolicy(
+	phase packagesv1alpha1.ObjectPhase, obj *unstructured.Unstructured,
+) (metav1.DeletionPropagation, bool, error) {
+	if policy, ok := obj.GetAnnotations()[packagesv1alpha1.RecreateOnImmutableChangeAnnotation]; ok {
+		switch propagation := metav1.DeletionPropagation(policy); propagation {
+		case metav1.DeletePropagationBackground,
+			metav1.DeletePropagationForeground,
+			metav1.DeletePropagationOrphan:
+			return propagation, true, nil
+		}
+		return "", false, fmt.Errorf("invalid %s annotation %q",
+			packagesv1alpha1.RecreateOnImmutableChangeAnnotation, policy)
+	}
+
+	if phase.RecreateOnImmutableChange == nil {
+		return "", false, nil
+	}
+	if len(phase.RecreateOnImmutableChange.PropagationPolicy) == 0 {
+		return metav1.DeletePropagationBackground, true, nil
+	}
+	return phase.RecreateOnImmutableChange.PropagationPolicy, true, nil
+}
+
+func getDeletionPolicy(obj *unstructured.Unstructured) (packagesv1alpha1.DeletionPolicy, error) {
+	policy := packagesv1alpha1.DeletionPolicy(
+		obj.GetAnnotations()[packagesv1alpha1.DeletionPolicyAnnotation])
+	switch policy {
+	case "":
+		return packagesv1alpha1.DeletionPolicyDelete, nil
+	case packagesv1alpha1.DeletionPolicyDelete,
+		packagesv1alpha1.DeletionPolicyOrphan:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid %s annotation %q", packagesv1alpha1.DeletionPolicyAnnotation, policy)
+}
+
+func newFailedObject(
+	obj *unstructured.Unstructured, message string,
+) packagesv1alpha1.ObjectPhaseFailedObject {
+	gvk := obj.GroupVersionKind()
+	return packagesv1alpha1.ObjectPhaseFailedObject{
+		Group:     gvk.Group,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Message:   message,
 	}
-	return obj, nil
 }
@@ -0,0 +1,539 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/ownerhandling"
+	"github.com/thetechnick/package-operator/internal/testutil"
+)
+
+type phaseOwnerMock struct {
+	PhaseOwner
+	obj            client.Object
+	adoptionPolicy packagesv1alpha1.ObjectSetAdoptionPolicy
+	driftPolicy    packagesv1alpha1.ObjectSetDriftPolicy
+}
+
+func (m *phaseOwnerMock) ClientObject() client.Object { return m.obj }
+
+func (m *phaseOwnerMock) GetAdoptionPolicy() packagesv1alpha1.ObjectSetAdoptionPolicy {
+	return m.adoptionPolicy
+}
+
+func (m *phaseOwnerMock) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return m.driftPolicy
+}
+
+type pausedPhaseOwnerMock struct {
+	PhaseOwner
+	obj client.Object
+}
+
+func (m *pausedPhaseOwnerMock) ClientObject() client.Object           { return m.obj }
+func (m *pausedPhaseOwnerMock) IsObjectPaused(obj client.Object) bool { return true }
+
+type dynamicWatcherMock struct{}
+
+func (dynamicWatcherMock) Watch(owner client.Object, obj runtime.Object) error { return nil }
+
+// Fails for every object, reporting its name.
+type namedFailureProbe struct{}
+
+func (namedFailureProbe) Probe(obj *unstructured.Unstructured) (bool, string) {
+	return false, obj.GetName()
+}
+
+func TestPhaseReconciler_Reconcile_concurrency(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+
+	const objects = 10
+	phase := packagesv1alpha1.ObjectPhase{Name: "test", MaxConcurrency: 4}
+	for i := 0; i < objects; i++ {
+		phase.Objects = append(phase.Objects, packagesv1alpha1.ObjectSetObject{
+			Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm-%d"}}`, i))},
+		})
+	}
+
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			// Later objects finish first.
+			var i int
+			_, _ = fmt.Sscanf(args.Get(1).(client.ObjectKey).Name, "cm-%d", &i)
+			time.Sleep(time.Duration(objects-i) * time.Millisecond)
+		}).
+		Return(nil)
+
+	r := NewPhaseReconciler(
+		dynamicWatcherMock{}, c, c, scheme, nil, nil, ownerhandling.Native,
+		packagesv1alpha1.ObjectSetApplyModeMergePatch, 1)
+	owner := &pausedPhaseOwnerMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test"},
+	}}
+
+	result, err := r.Reconcile(context.Background(), owner, phase, namedFailureProbe{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(objects), result.Objects)
+	if assert.Len(t, result.FailedObjects, objects) {
+		for i, failed := range result.FailedObjects {
+			assert.Equal(t, fmt.Sprintf("cm-%d", i), failed.Message)
+		}
+	}
+	c.AssertNumberOfCalls(t, "Get", objects)
+}
+
+// Owner applying all objects with the default apply mode and drift policy.
+type applyingPhaseOwnerMock struct {
+	PhaseOwner
+	obj client.Object
+}
+
+func (m *applyingPhaseOwnerMock) ClientObject() client.Object           { return m.obj }
+func (m *applyingPhaseOwnerMock) IsObjectPaused(obj client.Object) bool { return false }
+func (m *applyingPhaseOwnerMock) GetApplyMode() packagesv1alpha1.ObjectSetApplyMode {
+	return ""
+}
+func (m *applyingPhaseOwnerMock) GetDriftPolicy() packagesv1alpha1.ObjectSetDriftPolicy {
+	return ""
+}
+
+func TestPhaseReconciler_Reconcile_concurrentApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+
+	const (
+		objects     = 12
+		concurrency = 3
+	)
+	phase := packagesv1alpha1.ObjectPhase{Name: "test"}
+	for i := 0; i < objects; i++ {
+		phase.Objects = append(phase.Objects, packagesv1alpha1.ObjectSetObject{
+			Object: runtime.RawExtension{Raw: []byte(fmt.Sprintf(
+				`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm-%d"}}`, i))},
+		})
+	}
+
+	var inFlight, maxInFlight int32
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Return(errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, ""))
+	c.On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+					break
+				}
+			}
+
+			// Later objects finish first.
+			var i int
+			_, _ = fmt.Sscanf(args.Get(1).(*unstructured.Unstructured).GetName(), "cm-%d", &i)
+			time.Sleep(time.Duration(objects-i) * time.Millisecond)
+		}).
+		Return(nil)
+
+	r := NewPhaseReconciler(
+		dynamicWatcherMock{}, c, c, scheme, nil, nil, ownerhandling.Native,
+		packagesv1alpha1.ObjectSetApplyModeServerSideApply, concurrency)
+	owner := &applyingPhaseOwnerMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test", UID: "owner-uid"},
+	}}
+
+	result, err := r.Reconcile(context.Background(), owner, phase, namedFailureProbe{})
+	require.NoError(t, err)
+
+	c.AssertNumberOfCalls(t, "Patch", objects)
+	assert.LessOrEqual(t, maxInFlight, int32(concurrency))
+	assert.Greater(t, maxInFlight, int32(1), "objects should be applied concurrently")
+
+	assert.Equal(t, int32(objects), result.Objects)
+	if assert.Len(t, result.FailedObjects, objects) {
+		for i, failed := range result.FailedObjects {
+			assert.Equal(t, fmt.Sprintf("cm-%d", i), failed.Message)
+		}
+	}
+}
+
+func TestPhaseReconciler_recordObjectEvent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+
+	recorder := record.NewFakeRecorder(1)
+	objectRecorder := record.NewFakeRecorder(1)
+	r := &PhaseReconciler{scheme: scheme, recorder: recorder, objectRecorder: objectRecorder}
+
+	owner := &pausedPhaseOwnerMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test"},
+	}}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	obj.SetNamespace("test")
+	obj.SetName("nginx")
+
+	r.recordObjectEvent(owner, obj, "Adopted", "adopted")
+	assert.Equal(t, "Normal Adopted apps Deployment test/nginx was adopted", <-recorder.Events)
+	assert.Equal(t, "Normal Adopted Object was adopted by ObjectSet test/owner", <-objectRecorder.Events)
+}
+
+func TestPhaseReconciler_checkAdoption(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+
+	deploymentRef := func(name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: packagesv1alpha1.GroupVersion.String(), Kind: "ObjectDeployment",
+			Name: name, UID: types.UID(name), Controller: pointer.BoolPtr(true),
+		}}
+	}
+	newObjectSet := func(name, deployment string) *packagesv1alpha1.ObjectSet {
+		return &packagesv1alpha1.ObjectSet{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "test", UID: types.UID(name),
+			OwnerReferences: deploymentRef(deployment),
+		}}
+	}
+	owner := newObjectSet("owner", "deploy")
+	sibling := newObjectSet("sibling", "deploy")
+	other := newObjectSet("other", "other-deploy")
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sibling, other).Build()
+	r := &PhaseReconciler{ownerStrategy: ownerhandling.Native, scheme: scheme, sliceReader: reader}
+
+	controlledBy := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetLabels(map[string]string{ObjectSetLabelKey: "test-" + name})
+		obj.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: apiVersion, Kind: kind, Name: name,
+			UID: types.UID(name), Controller: pointer.BoolPtr(true),
+		}})
+		return obj
+	}
+	unmanaged := &unstructured.Unstructured{}
+	controlled := controlledBy("apps/v1", "Deployment", "test")
+	siblingControlled := controlledBy(packagesv1alpha1.GroupVersion.String(), "ObjectSet", "sibling")
+	otherControlled := controlledBy(packagesv1alpha1.GroupVersion.String(), "ObjectSet", "other")
+	labelled := &unstructured.Unstructured{}
+	labelled.SetLabels(map[string]string{ObjectSetLabelKey: "test-sibling"})
+	overridden := &unstructured.Unstructured{}
+	overridden.SetAnnotations(map[string]string{
+		packagesv1alpha1.AdoptionPolicyAnnotation: string(packagesv1alpha1.ObjectSetAdoptionPolicyAlways),
+	})
+
+	tests := []struct {
+		name            string
+		policy          packagesv1alpha1.ObjectSetAdoptionPolicy
+		obj, currentObj *unstructured.Unstructured
+		expectedErr     string
+	}{
+		{
+			name:        "Never",
+			policy:      packagesv1alpha1.ObjectSetAdoptionPolicyNever,
+			obj:         unmanaged,
+			currentObj:  unmanaged,
+			expectedErr: "adoption conflict: pre-existing object is not managed by package-operator",
+		},
+		{
+			name:       "Never, handover from sibling ObjectSet",
+			policy:     packagesv1alpha1.ObjectSetAdoptionPolicyNever,
+			obj:        unmanaged,
+			currentObj: siblingControlled,
+		},
+		{
+			name:        "Never, ObjectSet of other ObjectDeployment",
+			policy:      packagesv1alpha1.ObjectSetAdoptionPolicyNever,
+			obj:         unmanaged,
+			currentObj:  otherControlled,
+			expectedErr: `adoption conflict: controlled by packages.thetechnick.ninja/v1alpha1 ObjectSet "other"`,
+		},
+		{
+			name:        "Never, only labelled",
+			policy:      packagesv1alpha1.ObjectSetAdoptionPolicyNever,
+			obj:         unmanaged,
+			currentObj:  labelled,
+			expectedErr: "adoption conflict: pre-existing object is not managed by package-operator",
+		},
+		{
+			name:       "Never, overridden by annotation",
+			policy:     packagesv1alpha1.ObjectSetAdoptionPolicyNever,
+			obj:        overridden,
+			currentObj: controlled,
+		},
+		{
+			name:       "default",
+			obj:        unmanaged,
+			currentObj: unmanaged,
+		},
+		{
+			name:       "IfNoController",
+			policy:     packagesv1alpha1.ObjectSetAdoptionPolicyIfNoController,
+			obj:        unmanaged,
+			currentObj: unmanaged,
+		},
+		{
+			name:        "IfNoController, controlled",
+			policy:      packagesv1alpha1.ObjectSetAdoptionPolicyIfNoController,
+			obj:         unmanaged,
+			currentObj:  controlled,
+			expectedErr: `adoption conflict: controlled by apps/v1 Deployment "test"`,
+		},
+		{
+			name:       "Always",
+			policy:     packagesv1alpha1.ObjectSetAdoptionPolicyAlways,
+			obj:        unmanaged,
+			currentObj: controlled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := r.checkAdoption(context.Background(),
+				&phaseOwnerMock{obj: owner, adoptionPolicy: test.policy}, test.obj, test.currentObj)
+			if len(test.expectedErr) > 0 {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestImmutableFieldChanges(t *testing.T) {
+	gk := schema.GroupKind{Group: "batch", Kind: "Job"}
+	tests := []struct {
+		name           string
+		err            error
+		expectedFields []string
+		expectedOK     bool
+	}{
+		{
+			name: "immutable field",
+			err: errors.NewInvalid(gk, "test", field.ErrorList{
+				field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+			}),
+			expectedFields: []string{"spec.template"},
+			expectedOK:     true,
+		},
+		{
+			name: "StatefulSet spec",
+			err: errors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "StatefulSet"}, "test", field.ErrorList{
+				field.Forbidden(field.NewPath("spec"),
+					"updates to statefulset spec for fields other than 'replicas' are forbidden"),
+			}),
+			expectedFields: []string{"spec"},
+			expectedOK:     true,
+		},
+		{
+			name: "other validation error",
+			err: errors.NewInvalid(gk, "test", field.ErrorList{
+				field.Required(field.NewPath("spec", "template"), ""),
+			}),
+		},
+		{
+			name: "not invalid",
+			err:  errors.NewConflict(schema.GroupResource{Group: "batch", Resource: "jobs"}, "test", nil),
+		},
+		{name: "nil"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields, ok := immutableFieldChanges(test.err)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedFields, fields)
+		})
+	}
+}
+
+func TestGetRecreatePropagationPolicy(t *testing.T) {
+	annotated := &unstructured.Unstructured{}
+	annotated.SetAnnotations(map[string]string{
+		packagesv1alpha1.RecreateOnImmutableChangeAnnotation: "Foreground",
+	})
+
+	propagation, enabled, err := getRecreatePropagationPolicy(
+		packagesv1alpha1.ObjectPhase{}, &unstructured.Unstructured{})
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+	assert.Empty(t, propagation)
+
+	propagation, enabled, err = getRecreatePropagationPolicy(packagesv1alpha1.ObjectPhase{
+		RecreateOnImmutableChange: &packagesv1alpha1.RecreateOnImmutableChange{},
+	}, &unstructured.Unstructured{})
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	assert.Equal(t, metav1.DeletePropagationBackground, propagation)
+
+	propagation, enabled, err = getRecreatePropagationPolicy(
+		packagesv1alpha1.ObjectPhase{}, annotated)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	assert.Equal(t, metav1.DeletePropagationForeground, propagation)
+}
+
+func TestForceOwnership(t *testing.T) {
+	tests := []struct {
+		driftPolicy packagesv1alpha1.ObjectSetDriftPolicy
+		isOwner     bool
+		force       bool
+	}{
+		{driftPolicy: packagesv1alpha1.ObjectSetDriftPolicyCorrect, isOwner: true, force: true},
+		{driftPolicy: "", isOwner: true, force: true},
+		{driftPolicy: packagesv1alpha1.ObjectSetDriftPolicyReport, isOwner: true, force: false},
+		{driftPolicy: packagesv1alpha1.ObjectSetDriftPolicyIgnore, isOwner: true, force: false},
+		{driftPolicy: packagesv1alpha1.ObjectSetDriftPolicyReport, isOwner: false, force: true},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s owner=%v", test.driftPolicy, test.isOwner), func(t *testing.T) {
+			owner := &phaseOwnerMock{driftPolicy: test.driftPolicy}
+			assert.Equal(t, test.force, forceOwnership(owner, test.isOwner))
+		})
+	}
+}
+
+func TestPhaseReconciler_migrateUpdateManagers(t *testing.T) {
+	c := testutil.NewClient()
+	c.On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	r := &PhaseReconciler{client: c}
+
+	currentObj := &unstructured.Unstructured{}
+	currentObj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager: "package-operator-manager", Operation: metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:a":{},"f:old":{}}}`)},
+		},
+		{
+			Manager: FieldOwner, Operation: metav1.ManagedFieldsOperationApply,
+			APIVersion: "v1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:a":{},"f:b":{}}}`)},
+		},
+		{
+			Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate,
+			APIVersion: "v1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:c":{}}}`)},
+		},
+	})
+
+	require.NoError(t, r.migrateUpdateManagers(context.Background(), currentObj))
+	c.AssertNumberOfCalls(t, "Patch", 1)
+
+	managedFields := currentObj.GetManagedFields()
+	if assert.Len(t, managedFields, 2) {
+		assert.Equal(t, FieldOwner, managedFields[0].Manager)
+		assert.Equal(t, metav1.ManagedFieldsOperationApply, managedFields[0].Operation)
+		assert.JSONEq(t, `{"f:data":{"f:a":{},"f:b":{},"f:old":{}}}`, string(managedFields[0].FieldsV1.Raw))
+		assert.Equal(t, "kubectl", managedFields[1].Manager)
+	}
+
+	// nothing left to migrate.
+	require.NoError(t, r.migrateUpdateManagers(context.Background(), currentObj))
+	c.AssertNumberOfCalls(t, "Patch", 1)
+}
+
+func TestPhaseReconciler_migrateUpdateManagers_withoutApplyEntry(t *testing.T) {
+	c := testutil.NewClient()
+	c.On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	r := &PhaseReconciler{client: c}
+
+	currentObj := &unstructured.Unstructured{}
+	currentObj.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager: FieldOwner, Operation: metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:a":{}}}`)},
+	}})
+
+	require.NoError(t, r.migrateUpdateManagers(context.Background(), currentObj))
+	managedFields := currentObj.GetManagedFields()
+	if assert.Len(t, managedFields, 1) {
+		assert.Equal(t, FieldOwner, managedFields[0].Manager)
+		assert.Equal(t, metav1.ManagedFieldsOperationApply, managedFields[0].Operation)
+		assert.JSONEq(t, `{"f:data":{"f:a":{}}}`, string(managedFields[0].FieldsV1.Raw))
+	}
+}
+
+func TestPhaseReconciler_isChanged(t *testing.T) {
+	newContainer := func(cpu string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": "test", "namespace": "test"},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{
+					"name": "test",
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"cpu": cpu},
+					},
+				}},
+			},
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		desired   string
+		current   string
+		dryRunCPU string // value stored by the API server, if dry-run.
+		changed   bool
+	}{
+		{name: "unchanged", desired: "1", current: "1", changed: false},
+		{name: "normalized quantity", desired: "1000m", current: "1", dryRunCPU: "1", changed: false},
+		{name: "drifted", desired: "1000m", current: "2", dryRunCPU: "1", changed: true},
+	}
+	for _, test := range tests {
+		for _, applyMode := range []packagesv1alpha1.ObjectSetApplyMode{
+			packagesv1alpha1.ObjectSetApplyModeServerSideApply,
+			packagesv1alpha1.ObjectSetApplyModeMergePatch,
+		} {
+			t.Run(fmt.Sprintf("%s %s", test.name, applyMode), func(t *testing.T) {
+				c := testutil.NewClient()
+				c.On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Run(func(args mock.Arguments) {
+						obj := args.Get(1).(*unstructured.Unstructured)
+						*obj = *newContainer(test.dryRunCPU)
+						obj.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: FieldOwner}})
+					}).
+					Return(nil)
+				r := &PhaseReconciler{client: c}
+
+				current := newContainer(test.current)
+				current.SetResourceVersion("1")
+				changed := r.isChanged(context.Background(), applyMode, newContainer(test.desired), current)
+				assert.Equal(t, test.changed, changed)
+
+				if len(test.dryRunCPU) == 0 {
+					c.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+					return
+				}
+				c.AssertCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything,
+					mock.MatchedBy(func(opts []client.PatchOption) bool {
+						for _, opt := range opts {
+							if opt == client.DryRunAll {
+								return true
+							}
+						}
+						return false
+					}))
+			})
+		}
+	}
+}
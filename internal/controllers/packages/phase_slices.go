@@ -0,0 +1,148 @@
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+const (
+	// Keeps slices around until the ObjectSet owning them is torn down,
+	// so its objects can still be looked up during teardown.
+	SliceFinalizer = "packages.thetechnick.ninja/slice-teardown"
+	// JSON list of the positions the objects of a slice had in their phase,
+	// before the phase was split into slices.
+	SliceObjectIndicesAnnotation = "packages.thetechnick.ninja/object-indices"
+)
+
+// Returns the phase with the objects of its slices merged with the inline objects.
+// Objects are restored to their original position in the phase,
+// objects of slices without recorded positions are appended to the inline objects.
+// Cluster-scoped owners reference ClusterObjectSetSlices,
+// namespaced owners ObjectSetSlices in their namespace.
+func ResolveSlices(
+	ctx context.Context, c client.Reader,
+	owner client.Object, phase packagesv1alpha1.ObjectPhase,
+) (packagesv1alpha1.ObjectPhase, error) {
+	if len(phase.Slices) == 0 {
+		return phase, nil
+	}
+
+	var (
+		indexed   = map[int]packagesv1alpha1.ObjectSetObject{}
+		unindexed = append([]packagesv1alpha1.ObjectSetObject{}, phase.Objects...)
+	)
+	for _, name := range phase.Slices {
+		slice := newSlice(owner.GetNamespace(), name)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(slice), slice); err != nil {
+			return phase, fmt.Errorf("getting slice %q of phase %q: %w", name, phase.Name, err)
+		}
+
+		objects := sliceObjects(slice)
+		indices, err := sliceObjectIndices(slice, len(objects))
+		if err != nil {
+			return phase, fmt.Errorf("slice %q of phase %q: %w", name, phase.Name, err)
+		}
+		if indices == nil {
+			unindexed = append(unindexed, objects...)
+			continue
+		}
+		for i, index := range indices {
+			if _, ok := indexed[index]; ok {
+				return phase, fmt.Errorf("slice %q of phase %q: duplicate object index %d", name, phase.Name, index)
+			}
+			indexed[index] = objects[i]
+		}
+	}
+
+	objects := make([]packagesv1alpha1.ObjectSetObject, len(indexed)+len(unindexed))
+	for i := range objects {
+		if obj, ok := indexed[i]; ok {
+			objects[i] = obj
+			continue
+		}
+		if len(unindexed) == 0 {
+			return phase, fmt.Errorf("phase %q: no object at index %d", phase.Name, i)
+		}
+		objects[i] = unindexed[0]
+		unindexed = unindexed[1:]
+	}
+	phase.Objects = objects
+	phase.Slices = nil
+	return phase, nil
+}
+
+// Returns the recorded object indices of the slice, or nil if there are none.
+func sliceObjectIndices(slice client.Object, objects int) ([]int, error) {
+	annotation, ok := slice.GetAnnotations()[SliceObjectIndicesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var indices []int
+	if err := json.Unmarshal([]byte(annotation), &indices); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", SliceObjectIndicesAnnotation, err)
+	}
+	if len(indices) != objects {
+		return nil, fmt.Errorf("%s annotation lists %d indices for %d objects",
+			SliceObjectIndicesAnnotation, len(indices), objects)
+	}
+	return indices, nil
+}
+
+// Removes the SliceFinalizer from all slices of the given phases,
+// after the owner finished tearing down their objects.
+func ReleaseSlices(
+	ctx context.Context, c client.Client,
+	owner client.Object, phases []packagesv1alpha1.ObjectPhase,
+) error {
+	for _, phase := range phases {
+		for _, name := range phase.Slices {
+			slice := newSlice(owner.GetNamespace(), name)
+			err := c.Get(ctx, client.ObjectKeyFromObject(slice), slice)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("getting slice %q of phase %q: %w", name, phase.Name, err)
+			}
+			if !controllerutil.ContainsFinalizer(slice, SliceFinalizer) {
+				continue
+			}
+
+			controllerutil.RemoveFinalizer(slice, SliceFinalizer)
+			if err := c.Update(ctx, slice); err != nil {
+				return fmt.Errorf("removing finalizer from slice %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func newSlice(namespace, name string) client.Object {
+	if len(namespace) == 0 {
+		slice := &packagesv1alpha1.ClusterObjectSetSlice{}
+		slice.Name = name
+		return slice
+	}
+
+	slice := &packagesv1alpha1.ObjectSetSlice{}
+	slice.Name = name
+	slice.Namespace = namespace
+	return slice
+}
+
+func sliceObjects(slice client.Object) []packagesv1alpha1.ObjectSetObject {
+	switch s := slice.(type) {
+	case *packagesv1alpha1.ClusterObjectSetSlice:
+		return s.Objects
+	case *packagesv1alpha1.ObjectSetSlice:
+		return s.Objects
+	}
+	panic("unsupported slice type")
+}
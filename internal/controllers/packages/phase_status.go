@@ -0,0 +1,187 @@
+package packages
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+// Maximum number of failed objects reported in a phase status.
+const maxReportedFailedObjects = 10
+
+// Probe results of all objects in a phase.
+type PhaseProbeResult struct {
+	// Number of objects in the phase.
+	Objects int32
+	// Number of objects passing their probes.
+	ReadyObjects int32
+	// Objects failing their probes.
+	FailedObjects []packagesv1alpha1.ObjectPhaseFailedObject
+	// Objects that could not be applied due to field manager conflicts.
+	Conflicts []packagesv1alpha1.ObjectPhaseFailedObject
+	// Objects drifting from their desired state, that have not been corrected.
+	DriftedObjects []packagesv1alpha1.ObjectPhaseFailedObject
+	// Number of objects corrected back to their desired state.
+	DriftCorrections int64
+	// Pre-existing objects that could not be adopted.
+	AdoptionConflicts []packagesv1alpha1.ObjectPhaseFailedObject
+	// Objects that could not be updated, because immutable fields changed.
+	ImmutableFieldConflicts []packagesv1alpha1.ObjectPhaseFailedObject
+}
+
+// Returns true if all objects pass their probes.
+func (r PhaseProbeResult) Ready() bool {
+	return len(r.FailedObjects) == 0
+}
+
+// Summarizes the failed objects into a message
+// that stays readable for phases with many objects.
+func (r PhaseProbeResult) Message() string {
+	var messages []string
+	for i, failed := range r.FailedObjects {
+		if i == maxReportedFailedObjects {
+			messages = append(messages,
+				fmt.Sprintf("and %d more", len(r.FailedObjects)-maxReportedFailedObjects))
+			break
+		}
+		messages = append(messages, fmt.Sprintf("%s %s %s/%s: %s",
+			failed.Group, failed.Kind, failed.Namespace, failed.Name, failed.Message))
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Builds the status of a phase from its probe results.
+// LastTransitionTime is kept from the previous status of the phase,
+// unless readiness changed since the last check.
+func NewPhaseStatus(
+	previous []packagesv1alpha1.ObjectPhaseStatus,
+	name string, ready bool, result PhaseProbeResult, now metav1.Time,
+) packagesv1alpha1.ObjectPhaseStatus {
+	failedObjects := result.FailedObjects
+	if len(failedObjects) > maxReportedFailedObjects {
+		failedObjects = failedObjects[:maxReportedFailedObjects]
+	}
+	conflicts := result.Conflicts
+	if len(conflicts) > maxReportedFailedObjects {
+		conflicts = conflicts[:maxReportedFailedObjects]
+	}
+
+	phaseStatus := packagesv1alpha1.ObjectPhaseStatus{
+		Name:               name,
+		State:              packagesv1alpha1.ObjectPhaseStateReconciling,
+		Objects:            result.Objects,
+		ReadyObjects:       result.ReadyObjects,
+		FailedObjects:      failedObjects,
+		Conflicts:          conflicts,
+		Ready:              ready,
+		LastTransitionTime: &now,
+	}
+	if ready {
+		phaseStatus.State = packagesv1alpha1.ObjectPhaseStateReady
+	}
+
+	for _, prev := range previous {
+		if prev.Name == name && prev.Ready == ready &&
+			prev.LastTransitionTime != nil &&
+			prev.State != packagesv1alpha1.ObjectPhaseStatePending {
+			phaseStatus.LastTransitionTime = prev.LastTransitionTime
+		}
+	}
+	return phaseStatus
+}
+
+// Updates the drift summary with drift detected in the given phase results.
+func NewDriftStatus(
+	previous *packagesv1alpha1.ObjectSetDriftStatus,
+	results []PhaseProbeResult, now metav1.Time,
+) *packagesv1alpha1.ObjectSetDriftStatus {
+	drift := &packagesv1alpha1.ObjectSetDriftStatus{}
+	if previous != nil {
+		drift.Corrections = previous.Corrections
+		drift.LastDetectionTime = previous.LastDetectionTime
+	}
+
+	for _, result := range results {
+		drift.Corrections += result.DriftCorrections
+		drift.Objects = append(drift.Objects, result.DriftedObjects...)
+		if result.DriftCorrections > 0 {
+			drift.LastDetectionTime = &now
+		}
+		for _, drifted := range result.DriftedObjects {
+			if !isDriftReported(previous, drifted) {
+				drift.LastDetectionTime = &now
+			}
+			drift.ObjectHashes = append(drift.ObjectHashes, driftedObjectHash(drifted))
+		}
+	}
+	if len(drift.Objects) > maxReportedFailedObjects {
+		drift.Objects = drift.Objects[:maxReportedFailedObjects]
+	}
+
+	if drift.Corrections == 0 && drift.LastDetectionTime == nil {
+		return nil
+	}
+	return drift
+}
+
+// Sets or removes the AdoptionConflict and ImmutableFieldConflict conditions
+// based on the given phase results.
+func SetConflictConditions(
+	conditions *[]metav1.Condition, generation int64, results []PhaseProbeResult,
+) {
+	var adoptionConflicts, immutableFieldConflicts []packagesv1alpha1.ObjectPhaseFailedObject
+	for _, result := range results {
+		adoptionConflicts = append(adoptionConflicts, result.AdoptionConflicts...)
+		immutableFieldConflicts = append(immutableFieldConflicts, result.ImmutableFieldConflicts...)
+	}
+	setConflictCondition(conditions, generation,
+		packagesv1alpha1.ObjectSetAdoptionConflict, adoptionConflicts)
+	setConflictCondition(conditions, generation,
+		packagesv1alpha1.ObjectSetImmutableFieldConflict, immutableFieldConflicts)
+}
+
+func setConflictCondition(
+	conditions *[]metav1.Condition, generation int64,
+	conditionType string, conflicts []packagesv1alpha1.ObjectPhaseFailedObject,
+) {
+	if len(conflicts) == 0 {
+		meta.RemoveStatusCondition(conditions, conditionType)
+		return
+	}
+
+	var messages []string
+	for _, conflict := range conflicts {
+		messages = append(messages, fmt.Sprintf("%s %s %s/%s: %s",
+			conflict.Group, conflict.Kind, conflict.Namespace, conflict.Name, conflict.Message))
+	}
+
+	if len(messages) > maxReportedFailedObjects {
+		messages = append(messages[:maxReportedFailedObjects],
+			fmt.Sprintf("and %d more", len(messages)-maxReportedFailedObjects))
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             conditionType,
+		Message:            strings.Join(messages, ", "),
+		ObservedGeneration: generation,
+	})
+}
+
+// Status of a phase that has not been reached yet,
+// because the given phases it depends on are not ready.
+// Slices of the phase have to be resolved, so their objects are counted.
+func NewPendingPhaseStatus(
+	phase packagesv1alpha1.ObjectPhase, blockedBy []string,
+) packagesv1alpha1.ObjectPhaseStatus {
+	return packagesv1alpha1.ObjectPhaseStatus{
+		Name:      phase.Name,
+		State:     packagesv1alpha1.ObjectPhaseStatePending,
+		Objects:   int32(len(phase.Objects)),
+		BlockedBy: blockedBy,
+	}
+}
@@ -0,0 +1,98 @@
+package packages
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestPhaseProbeResult_Message(t *testing.T) {
+	result := PhaseProbeResult{Objects: 12}
+	for i := 0; i < 12; i++ {
+		result.FailedObjects = append(result.FailedObjects, packagesv1alpha1.ObjectPhaseFailedObject{
+			Group: "apps", Kind: "Deployment", Namespace: "test", Name: fmt.Sprintf("dep-%d", i),
+			Message: "not ready",
+		})
+	}
+
+	message := result.Message()
+	assert.Contains(t, message, "apps Deployment test/dep-0: not ready, ")
+	assert.NotContains(t, message, "dep-10")
+	assert.Contains(t, message, ", and 2 more")
+}
+
+func TestNewPhaseStatus(t *testing.T) {
+	earlier := metav1.NewTime(time.Now().Add(-time.Minute))
+	now := metav1.Now()
+	previous := []packagesv1alpha1.ObjectPhaseStatus{
+		{Name: "deploy", State: packagesv1alpha1.ObjectPhaseStateReady, Ready: true, LastTransitionTime: &earlier},
+	}
+
+	// readiness unchanged
+	phaseStatus := NewPhaseStatus(previous, "deploy", true, PhaseProbeResult{Objects: 1, ReadyObjects: 1}, now)
+	assert.Equal(t, packagesv1alpha1.ObjectPhaseStateReady, phaseStatus.State)
+	assert.Equal(t, &earlier, phaseStatus.LastTransitionTime)
+
+	// readiness changed
+	phaseStatus = NewPhaseStatus(previous, "deploy", false, PhaseProbeResult{
+		Objects:       1,
+		FailedObjects: []packagesv1alpha1.ObjectPhaseFailedObject{{Kind: "Deployment", Name: "test"}},
+	}, now)
+	assert.Equal(t, packagesv1alpha1.ObjectPhaseStateReconciling, phaseStatus.State)
+	assert.Equal(t, &now, phaseStatus.LastTransitionTime)
+}
+
+func TestNewDriftStatus(t *testing.T) {
+	earlier := metav1.NewTime(time.Now().Add(-time.Minute))
+	now := metav1.Now()
+	drifted := packagesv1alpha1.ObjectPhaseFailedObject{
+		Kind: "ConfigMap", Namespace: "test", Name: "cm", Message: `changed by "kubectl-edit"`,
+	}
+
+	// no drift
+	assert.Nil(t, NewDriftStatus(nil, []PhaseProbeResult{{}}, now))
+
+	// corrections accumulate
+	drift := NewDriftStatus(&packagesv1alpha1.ObjectSetDriftStatus{
+		Corrections: 2, LastDetectionTime: &earlier,
+	}, []PhaseProbeResult{{DriftCorrections: 1}}, now)
+	assert.Equal(t, int64(3), drift.Corrections)
+	assert.Equal(t, &now, drift.LastDetectionTime)
+
+	// already reported drift is not detected again
+	drift = NewDriftStatus(&packagesv1alpha1.ObjectSetDriftStatus{
+		LastDetectionTime: &earlier,
+		Objects:           []packagesv1alpha1.ObjectPhaseFailedObject{drifted},
+	}, []PhaseProbeResult{{DriftedObjects: []packagesv1alpha1.ObjectPhaseFailedObject{drifted}}}, now)
+	assert.Equal(t, &earlier, drift.LastDetectionTime)
+	assert.Equal(t, []packagesv1alpha1.ObjectPhaseFailedObject{drifted}, drift.Objects)
+}
+
+func TestNewDriftStatus_manyObjects(t *testing.T) {
+	earlier := metav1.NewTime(time.Now().Add(-time.Minute))
+	now := metav1.Now()
+	var drifted []packagesv1alpha1.ObjectPhaseFailedObject
+	for i := 0; i < maxReportedFailedObjects+5; i++ {
+		drifted = append(drifted, packagesv1alpha1.ObjectPhaseFailedObject{
+			Kind: "ConfigMap", Namespace: "test", Name: fmt.Sprintf("cm-%d", i),
+			Message: `changed by "kubectl-edit"`,
+		})
+	}
+	results := []PhaseProbeResult{{DriftedObjects: drifted}}
+
+	drift := NewDriftStatus(nil, results, earlier)
+	assert.Len(t, drift.Objects, maxReportedFailedObjects)
+	assert.Len(t, drift.ObjectHashes, len(drifted))
+	for _, d := range drifted {
+		assert.True(t, isDriftReported(drift, d), d.Name)
+	}
+
+	// persisting drift beyond the reported objects is not detected again
+	drift = NewDriftStatus(drift, results, now)
+	assert.Equal(t, &earlier, drift.LastDetectionTime)
+}
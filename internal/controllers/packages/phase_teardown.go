@@ -2,41 +2,245 @@ package packages
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/controllers"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Outcome of tearing down a phase.
+type TeardownResult struct {
+	// True if all objects are gone or have been released.
+	Done bool
+	// Deleted objects still waiting on finalizers.
+	Terminating []TerminatingObject
+	// Time until the next terminating object times out, if any.
+	RequeueAfter time.Duration
+}
+
+// Deleted object still waiting on finalizers.
+type TerminatingObject struct {
+	Group, Kind, Namespace, Name string
+	Finalizers                   []string
+	// True if the object is terminating for longer than the teardown timeout.
+	Stuck bool
+}
+
+// Adds the terminating objects of another result.
+// Done is left untouched, as it depends on which phases have been attempted.
+func (r *TeardownResult) Merge(other TeardownResult) {
+	r.Terminating = append(r.Terminating, other.Terminating...)
+	if other.RequeueAfter > 0 &&
+		(r.RequeueAfter == 0 || other.RequeueAfter < r.RequeueAfter) {
+		r.RequeueAfter = other.RequeueAfter
+	}
+}
+
+// Returns true if any object is stuck terminating.
+func (r TeardownResult) Stuck() bool {
+	for _, t := range r.Terminating {
+		if t.Stuck {
+			return true
+		}
+	}
+	return false
+}
+
+// Summarizes the terminating objects and the finalizers they are waiting on.
+func (r TeardownResult) Message() string {
+	var messages []string
+	for i, t := range r.Terminating {
+		if i == maxReportedFailedObjects {
+			messages = append(messages,
+				fmt.Sprintf("and %d more", len(r.Terminating)-maxReportedFailedObjects))
+			break
+		}
+		msg := fmt.Sprintf("%s %s %s/%s waiting on finalizers [%s]",
+			t.Group, t.Kind, t.Namespace, t.Name, strings.Join(t.Finalizers, ", "))
+		if t.Stuck {
+			msg += " (stuck)"
+		}
+		messages = append(messages, msg)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// Reports an object as terminating, or stuck if it exceeded the given timeout.
+// Returns the time until the object times out, or 0 if it is already stuck or there is no timeout.
+func NewTerminatingObject(
+	obj client.Object, timeout time.Duration, now time.Time,
+) (TerminatingObject, time.Duration) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	t := TerminatingObject{
+		Group:      gvk.Group,
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Finalizers: obj.GetFinalizers(),
+	}
+	deletionTimestamp := obj.GetDeletionTimestamp()
+	if timeout == 0 || deletionTimestamp == nil {
+		return t, 0
+	}
+	remaining := deletionTimestamp.Add(timeout).Sub(now)
+	if remaining <= 0 {
+		t.Stuck = true
+		return t, 0
+	}
+	return t, remaining
+}
+
+// Deletes all objects of the phase that are still controlled by the owner,
+// one by one in reverse order, using the teardown propagation policy of the phase.
+// Objects controlled by someone else or with the Orphan deletion policy
+// are released by removing the owner reference.
+// Objects stored in slices are read via the sliceReader.
 func TeardownPhase(
 	ctx context.Context,
 	c client.Client,
+	sliceReader client.Reader,
+	ownerStrategy ownerStrategy,
+	owner PausingClientObject,
+	phase packagesv1alpha1.ObjectPhase,
+) (TeardownResult, error) {
+	return teardownPhase(ctx, c, sliceReader, ownerStrategy, owner, phase, false)
+}
+
+// Releases all objects of the phase by removing the owner reference,
+// without deleting anything, including objects paused by the owner.
+func OrphanPhase(
+	ctx context.Context,
+	c client.Client,
+	sliceReader client.Reader,
+	ownerStrategy ownerStrategy,
+	owner PausingClientObject,
+	phase packagesv1alpha1.ObjectPhase,
+) (TeardownResult, error) {
+	return teardownPhase(ctx, c, sliceReader, ownerStrategy, owner, phase, true)
+}
+
+func teardownPhase(
+	ctx context.Context,
+	c client.Client,
+	sliceReader client.Reader,
+	ownerStrategy ownerStrategy,
 	owner PausingClientObject,
 	phase packagesv1alpha1.ObjectPhase,
-) (cleanupDone bool, err error) {
+	orphan bool,
+) (result TeardownResult, err error) {
+	log := controllers.LoggerFromContext(ctx)
+
+	// Slices are kept by the SliceFinalizer until the owner is torn down,
+	// so a missing slice blocks teardown instead of leaving its objects behind.
+	phase, err = ResolveSlices(ctx, sliceReader, owner.ClientObject(), phase)
+	if err != nil {
+		return result, err
+	}
+
 	var (
 		objectsToCleanup int
 		cleanupCounter   int
+		now              = time.Now()
+		timeout          = time.Duration(phase.TeardownTimeoutSeconds) * time.Second
 	)
-	for _, phaseObject := range phase.Objects {
-		obj, err := UnstructuredFromObjectObject(&phaseObject)
+	// Objects are deleted in reverse order, like phases are torn down in reverse order.
+	// Each object has to be gone, before the object preceding it is deleted.
+	for i := len(phase.Objects) - 1; i >= 0; i-- {
+		obj, err := UnstructuredFromObjectObject(&phase.Objects[i])
 		if err != nil {
-			return false, err
+			return result, err
+		}
+		if len(obj.GetNamespace()) == 0 {
+			obj.SetNamespace(owner.ClientObject().GetNamespace())
 		}
-		obj.SetNamespace(owner.ClientObject().GetNamespace())
 
-		if !owner.IsObjectPaused(obj) {
-			objectsToCleanup++
+		currentObj := obj.DeepCopy()
+		err = c.Get(ctx, client.ObjectKeyFromObject(obj), currentObj)
+		if err != nil && !errors.IsNotFound(err) {
+			return result, fmt.Errorf("getting %s: %w", obj.GroupVersionKind(), err)
+		}
+		// Label selectors of paused objects match the labels of the live object.
+		if !orphan && owner.IsObjectPaused(currentObj) {
+			continue
+		}
+		objectsToCleanup++
+		if errors.IsNotFound(err) {
+			cleanupCounter++
+			continue
+		}
 
-			err := c.Delete(ctx, obj)
-			if err != nil && errors.IsNotFound(err) {
-				cleanupCounter++
-				continue
-			}
-			if err != nil {
-				return false, err
+		if !ownerStrategy.IsOwner(owner.ClientObject(), currentObj) {
+			// Not ours (anymore).
+			cleanupCounter++
+			continue
+		}
+
+		deletionPolicy, err := getDeletionPolicy(obj)
+		if err != nil {
+			return result, err
+		}
+
+		controller, hasController := ownerStrategy.GetController(currentObj)
+		if orphan || deletionPolicy == packagesv1alpha1.DeletionPolicyOrphan ||
+			!hasController || controller.UID != owner.ClientObject().GetUID() {
+			// Someone else took control or the object should be kept,
+			// just let go of the object.
+			log.Info("releasing object",
+				"obj", client.ObjectKeyFromObject(currentObj), "deletionPolicy", deletionPolicy)
+			updatedObj := currentObj.DeepCopy()
+			ownerStrategy.RemoveOwner(owner.ClientObject(), updatedObj)
+			if err := c.Patch(ctx, updatedObj, client.MergeFromWithOptions(
+				currentObj, client.MergeFromWithOptimisticLock{})); err != nil {
+				return result, fmt.Errorf("removing owner reference: %w", err)
 			}
+			cleanupCounter++
+			continue
+		}
+
+		if currentObj.GetDeletionTimestamp() != nil {
+			// Already deleted, waiting on finalizers.
+			result.addTerminating(currentObj, timeout, now)
+			return result, nil
+		}
+
+		// Only delete the exact object we control,
+		// not one that has been recreated by someone else in the meantime.
+		uid := currentObj.GetUID()
+		deleteOpts := []client.DeleteOption{client.Preconditions{UID: &uid}}
+		if len(phase.TeardownPropagationPolicy) > 0 {
+			deleteOpts = append(deleteOpts, client.PropagationPolicy(phase.TeardownPropagationPolicy))
+		}
+		err = c.Delete(ctx, currentObj, deleteOpts...)
+		if errors.IsNotFound(err) {
+			cleanupCounter++
+			continue
 		}
+		if errors.IsConflict(err) {
+			// Object was replaced, check again on the next reconcile.
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		// Wait for the object to be gone, its deletion triggers the next reconcile.
+		result.addTerminating(currentObj, timeout, now)
+		return result, nil
 	}
-	return cleanupCounter == objectsToCleanup, nil
+	result.Done = cleanupCounter == objectsToCleanup
+	return result, nil
+}
+
+func (r *TeardownResult) addTerminating(
+	obj *unstructured.Unstructured, timeout time.Duration, now time.Time,
+) {
+	terminating, remaining := NewTerminatingObject(obj, timeout, now)
+	r.Merge(TeardownResult{
+		Terminating:  []TerminatingObject{terminating},
+		RequeueAfter: remaining,
+	})
 }
@@ -0,0 +1,349 @@
+package packages
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+	"github.com/thetechnick/package-operator/internal/ownerhandling"
+	"github.com/thetechnick/package-operator/internal/testutil"
+)
+
+type pausingClientObjectMock struct {
+	obj       client.Object
+	pausedFor []packagesv1alpha1.ObjectSetPausedObject
+}
+
+func (m *pausingClientObjectMock) ClientObject() client.Object { return m.obj }
+func (m *pausingClientObjectMock) IsObjectPaused(obj client.Object) bool {
+	for _, pausedObject := range m.pausedFor {
+		if PausedObjectMatches(pausedObject, obj) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTeardownPhase(t *testing.T) {
+	owner := &pausingClientObjectMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test", UID: "owner-uid"},
+	}}
+	phase := packagesv1alpha1.ObjectPhase{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"controlled"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"taken-over"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foreign"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"orphaned",` +
+				`"annotations":{"packages.thetechnick.ninja/deletion-policy":"Orphan"}}}`)}},
+		},
+	}
+
+	ownerRefs := map[string][]metav1.OwnerReference{
+		"controlled": {
+			{Kind: "ObjectSet", Name: "owner", UID: "owner-uid", Controller: pointer.BoolPtr(true)},
+		},
+		"taken-over": {
+			{Kind: "ObjectSet", Name: "owner", UID: "owner-uid"},
+			{Kind: "ObjectSet", Name: "newer", UID: "newer-uid", Controller: pointer.BoolPtr(true)},
+		},
+		"foreign": nil,
+		"orphaned": {
+			{Kind: "ObjectSet", Name: "owner", UID: "owner-uid", Controller: pointer.BoolPtr(true)},
+		},
+	}
+
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			key := args.Get(1).(types.NamespacedName)
+			obj := args.Get(2).(*unstructured.Unstructured)
+			obj.SetUID(types.UID(key.Name + "-uid"))
+			obj.SetOwnerReferences(ownerRefs[key.Name])
+		}).
+		Return(nil)
+	c.On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	c.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	result, err := TeardownPhase(context.Background(), c, c, ownerhandling.Native, owner, phase)
+	require.NoError(t, err)
+	assert.False(t, result.Done) // controlled object is still being deleted
+
+	c.AssertNumberOfCalls(t, "Delete", 1)
+	var deleted mock.Call
+	for _, call := range c.Calls {
+		if call.Method == "Delete" {
+			deleted = call
+		}
+	}
+	deletedObj := deleted.Arguments.Get(1).(*unstructured.Unstructured)
+	assert.Equal(t, "controlled", deletedObj.GetName())
+	uid := types.UID("controlled-uid")
+	assert.Equal(t, []client.DeleteOption{client.Preconditions{UID: &uid}},
+		deleted.Arguments.Get(2))
+
+	c.AssertNumberOfCalls(t, "Patch", 2)
+	patchedOwnerRefs := map[string][]metav1.OwnerReference{}
+	for _, call := range c.Calls {
+		if call.Method == "Patch" {
+			patchedObj := call.Arguments.Get(1).(*unstructured.Unstructured)
+			patchedOwnerRefs[patchedObj.GetName()] = patchedObj.GetOwnerReferences()
+		}
+	}
+	assert.Equal(t, map[string][]metav1.OwnerReference{
+		"taken-over": {
+			{Kind: "ObjectSet", Name: "newer", UID: "newer-uid", Controller: pointer.BoolPtr(true)},
+		},
+		"orphaned": {},
+	}, patchedOwnerRefs)
+}
+
+func TestOrphanPhase(t *testing.T) {
+	owner := &pausingClientObjectMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test", UID: "owner-uid"},
+	}}
+	phase := packagesv1alpha1.ObjectPhase{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"controlled"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foreign"}}`)}},
+		},
+	}
+
+	ownerRefs := map[string][]metav1.OwnerReference{
+		"controlled": {
+			{Kind: "ObjectSet", Name: "owner", UID: "owner-uid", Controller: pointer.BoolPtr(true)},
+		},
+		"foreign": nil,
+	}
+
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			key := args.Get(1).(types.NamespacedName)
+			obj := args.Get(2).(*unstructured.Unstructured)
+			obj.SetOwnerReferences(ownerRefs[key.Name])
+		}).
+		Return(nil)
+	c.On("Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	result, err := OrphanPhase(context.Background(), c, c, ownerhandling.Native, owner, phase)
+	require.NoError(t, err)
+	assert.True(t, result.Done)
+
+	c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	c.AssertNumberOfCalls(t, "Patch", 1)
+	for _, call := range c.Calls {
+		if call.Method == "Patch" {
+			patchedObj := call.Arguments.Get(1).(*unstructured.Unstructured)
+			assert.Equal(t, "controlled", patchedObj.GetName())
+			assert.Empty(t, patchedObj.GetOwnerReferences())
+		}
+	}
+}
+
+func TestTeardownPhase_paused(t *testing.T) {
+	owner := &pausingClientObjectMock{
+		obj: &packagesv1alpha1.ObjectSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test", UID: "owner-uid"},
+		},
+		pausedFor: []packagesv1alpha1.ObjectSetPausedObject{
+			{Kind: "ConfigMap", Name: "cm", Namespace: "test"},
+			{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "role"},
+		},
+	}
+	phase := packagesv1alpha1.ObjectPhase{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(
+				`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"role"}}`)}},
+		},
+	}
+
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*unstructured.Unstructured)
+			if obj.GetKind() == "ClusterRole" {
+				// live cluster-scoped objects have no namespace.
+				obj.SetNamespace("")
+			}
+			obj.SetOwnerReferences([]metav1.OwnerReference{
+				{Kind: "ObjectSet", Name: "owner", UID: "owner-uid", Controller: pointer.BoolPtr(true)},
+			})
+		}).
+		Return(nil)
+
+	result, err := TeardownPhase(context.Background(), c, c, ownerhandling.Native, owner, phase)
+	require.NoError(t, err)
+	assert.True(t, result.Done)
+	c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeardownPhase_terminating(t *testing.T) {
+	owner := &pausingClientObjectMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test", UID: "owner-uid"},
+	}}
+	phase := packagesv1alpha1.ObjectPhase{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"first"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"second"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"stuck"}}`)}},
+		},
+		TeardownPropagationPolicy: metav1.DeletePropagationForeground,
+		TeardownTimeoutSeconds:    60,
+	}
+
+	deletedAt := metav1.NewTime(time.Now().Add(-time.Hour))
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			key := args.Get(1).(types.NamespacedName)
+			obj := args.Get(2).(*unstructured.Unstructured)
+			obj.SetUID(types.UID(key.Name + "-uid"))
+			obj.SetOwnerReferences([]metav1.OwnerReference{
+				{Kind: "ObjectSet", Name: "owner", UID: "owner-uid", Controller: pointer.BoolPtr(true)},
+			})
+			if key.Name == "stuck" {
+				obj.SetDeletionTimestamp(&deletedAt)
+				obj.SetFinalizers([]string{"example.com/cleanup"})
+			}
+		}).
+		Return(nil)
+	c.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	result, err := TeardownPhase(context.Background(), c, c, ownerhandling.Native, owner, phase)
+	require.NoError(t, err)
+	assert.False(t, result.Done)
+	assert.True(t, result.Stuck())
+	assert.Equal(t, []TerminatingObject{{
+		Kind: "ConfigMap", Namespace: "test", Name: "stuck",
+		Finalizers: []string{"example.com/cleanup"}, Stuck: true,
+	}}, result.Terminating)
+	assert.Equal(t, ` ConfigMap test/stuck waiting on finalizers [example.com/cleanup] (stuck)`, result.Message())
+
+	// earlier objects are not deleted, while later objects are terminating.
+	c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTeardownPhase_reverseOrder(t *testing.T) {
+	owner := &pausingClientObjectMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test", UID: "owner-uid"},
+	}}
+	phase := packagesv1alpha1.ObjectPhase{
+		Name: "test",
+		Objects: []packagesv1alpha1.ObjectSetObject{
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"first"}}`)}},
+			{Object: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"second"}}`)}},
+		},
+		TeardownPropagationPolicy: metav1.DeletePropagationForeground,
+	}
+
+	// names of objects that are terminating or gone.
+	deletedAt := metav1.Now()
+	terminating, gone := map[string]bool{}, map[string]bool{}
+	isGone := func(key types.NamespacedName) bool { return gone[key.Name] }
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, mock.MatchedBy(isGone), mock.Anything).
+		Return(errors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, ""))
+	c.On("Get", mock.Anything, mock.MatchedBy(func(key types.NamespacedName) bool {
+		return !isGone(key)
+	}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			key := args.Get(1).(types.NamespacedName)
+			obj := args.Get(2).(*unstructured.Unstructured)
+			obj.SetUID(types.UID(key.Name + "-uid"))
+			obj.SetOwnerReferences([]metav1.OwnerReference{
+				{Kind: "ObjectSet", Name: "owner", UID: "owner-uid", Controller: pointer.BoolPtr(true)},
+			})
+			if terminating[key.Name] {
+				obj.SetDeletionTimestamp(&deletedAt)
+			}
+		}).
+		Return(nil)
+	c.On("Delete", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			terminating[args.Get(1).(*unstructured.Unstructured).GetName()] = true
+		}).
+		Return(nil)
+
+	var deleted []string
+	teardown := func() TeardownResult {
+		c.Calls = nil
+		result, err := TeardownPhase(context.Background(), c, c, ownerhandling.Native, owner, phase)
+		require.NoError(t, err)
+		for _, call := range c.Calls {
+			if call.Method == "Delete" {
+				deleted = append(deleted, call.Arguments.Get(1).(*unstructured.Unstructured).GetName())
+				assert.Contains(t, call.Arguments.Get(2),
+					client.PropagationPolicy(metav1.DeletePropagationForeground))
+			}
+		}
+		return result
+	}
+
+	// the last object is deleted first.
+	result := teardown()
+	assert.False(t, result.Done)
+	if assert.Len(t, result.Terminating, 1) {
+		assert.Equal(t, "second", result.Terminating[0].Name)
+	}
+	assert.Equal(t, []string{"second"}, deleted)
+
+	// the first object is not deleted, while the second object is terminating.
+	result = teardown()
+	assert.False(t, result.Done)
+	if assert.Len(t, result.Terminating, 1) {
+		assert.Equal(t, "second", result.Terminating[0].Name)
+	}
+	assert.Equal(t, []string{"second"}, deleted)
+
+	// the first object is deleted, after the second object is gone.
+	gone["second"] = true
+	result = teardown()
+	assert.False(t, result.Done)
+	if assert.Len(t, result.Terminating, 1) {
+		assert.Equal(t, "first", result.Terminating[0].Name)
+	}
+	assert.Equal(t, []string{"second", "first"}, deleted)
+
+	gone["first"] = true
+	result = teardown()
+	assert.True(t, result.Done)
+	assert.Empty(t, result.Terminating)
+}
+
+func TestTeardownPhase_missingSlice(t *testing.T) {
+	owner := &pausingClientObjectMock{obj: &packagesv1alpha1.ObjectSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "test", UID: "owner-uid"},
+	}}
+	phase := packagesv1alpha1.ObjectPhase{Name: "test", Slices: []string{"owner-0-0"}}
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, packagesv1alpha1.AddToScheme(scheme))
+	sliceReader := fake.NewClientBuilder().WithScheme(scheme).Build()
+	c := testutil.NewClient()
+
+	// Objects of the missing slice can't be looked up, so teardown can't complete.
+	_, err := TeardownPhase(context.Background(), c, sliceReader, ownerhandling.Native, owner, phase)
+	if assert.Error(t, err) {
+		assert.True(t, errors.IsNotFound(err))
+	}
+	c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
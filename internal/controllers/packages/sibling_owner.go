@@ -0,0 +1,88 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+// Returns true if the given controller is of the same kind as the owner
+// and both are revisions of the same object, e.g. ObjectSets of the same ObjectDeployment.
+func IsSiblingOwner(
+	ctx context.Context, c client.Reader, scheme *runtime.Scheme,
+	owner client.Object, controller metav1.OwnerReference,
+) (bool, error) {
+	ownerGVK, err := apiutil.GVKForObject(owner, scheme)
+	if err != nil {
+		return false, err
+	}
+	if controller.APIVersion != ownerGVK.GroupVersion().String() ||
+		controller.Kind != ownerGVK.Kind {
+		return false, nil
+	}
+
+	sibling, err := getOwner(ctx, c, scheme, owner.GetNamespace(), controller)
+	if err != nil || sibling == nil {
+		return false, err
+	}
+
+	ownerParent, err := revisionParent(ctx, c, scheme, owner)
+	if err != nil || ownerParent == nil {
+		return false, err
+	}
+	siblingParent, err := revisionParent(ctx, c, scheme, sibling)
+	if err != nil || siblingParent == nil {
+		return false, err
+	}
+	return ownerParent.UID == siblingParent.UID, nil
+}
+
+// Returns the controller of the given ObjectSet or ObjectSetPhase,
+// that is not an ObjectSet itself, e.g. the ObjectDeployment.
+// Owners live next to each other, so only native owner references are followed.
+func revisionParent(
+	ctx context.Context, c client.Reader, scheme *runtime.Scheme, obj client.Object,
+) (*metav1.OwnerReference, error) {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil || (ref.Kind != "ObjectSet" && ref.Kind != "ClusterObjectSet") ||
+		ref.APIVersion != packagesv1alpha1.GroupVersion.String() {
+		return ref, nil
+	}
+
+	objectSet, err := getOwner(ctx, c, scheme, obj.GetNamespace(), *ref)
+	if err != nil || objectSet == nil {
+		return nil, err
+	}
+	return metav1.GetControllerOf(objectSet), nil
+}
+
+// Returns the owner referenced or nil, if it no longer exists.
+func getOwner(
+	ctx context.Context, c client.Reader, scheme *runtime.Scheme, namespace string, ref metav1.OwnerReference,
+) (client.Object, error) {
+	newObj, err := scheme.New(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	if err != nil {
+		return nil, err
+	}
+	obj := newObj.(client.Object)
+	if err := c.Get(ctx, client.ObjectKey{
+		Name: ref.Name, Namespace: namespace,
+	}, obj); errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting %s %q: %w", ref.Kind, ref.Name, err)
+	}
+	if len(ref.UID) > 0 && ref.UID != obj.GetUID() {
+		// owner was deleted and recreated.
+		return nil, nil
+	}
+	return obj, nil
+}
@@ -21,6 +21,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/thetechnick/package-operator/internal/metrics"
 )
 
 // DynamicWatcher is able to dynamically allocate new watches for arbitrary objects.
@@ -132,6 +134,9 @@ func (dw *DynamicWatcher) Watch(owner client.Object, obj runtime.Object) error {
 	if _, ok := dw.informers[ngvk]; !ok {
 		dw.informerReferences[ngvk] = map[OwnerRef]struct{}{}
 	}
+	if _, ok := dw.informerReferences[ngvk][ownerRef]; !ok {
+		metrics.DynamicWatcherOwnerReferences.WithLabelValues(gvk.Group, gvk.Kind).Inc()
+	}
 	dw.informerReferences[ngvk][ownerRef] = struct{}{}
 	if _, ok := dw.informers[ngvk]; ok {
 		dw.log.Info(
@@ -144,6 +149,7 @@ func (dw *DynamicWatcher) Watch(owner client.Object, obj runtime.Object) error {
 	// Adding new watcher.
 	informerStopChannel := make(chan struct{})
 	dw.informers[ngvk] = informerStopChannel
+	metrics.DynamicWatcherInformers.WithLabelValues(gvk.Group, gvk.Kind).Inc()
 	dw.log.Info("adding new watcher",
 		"owner", schema.GroupKind{Group: ownerRef.Group, Kind: ownerRef.Kind},
 		"for", schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, "namespace", owner.GetNamespace())
@@ -208,10 +214,12 @@ func (dw *DynamicWatcher) Free(owner client.Object) error {
 	for gvk, refs := range dw.informerReferences {
 		if _, ok := refs[ownerRef]; ok {
 			delete(refs, ownerRef)
+			metrics.DynamicWatcherOwnerReferences.WithLabelValues(gvk.Group, gvk.Kind).Dec()
 
 			if len(refs) == 0 {
 				close(dw.informers[gvk])
 				delete(dw.informers, gvk)
+				metrics.DynamicWatcherInformers.WithLabelValues(gvk.Group, gvk.Kind).Dec()
 				dw.log.Info("releasing watcher",
 					"kind", gvk.Kind, "group", gvk.Group, "namespace", owner.GetNamespace())
 			}
@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+// Counts drift of managed objects from their desired state.
+// action is either "Corrected" or "Reported".
+var ObjectDrift = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "package_operator_object_drift_total",
+		Help: "Number of times managed objects drifted from their desired state.",
+	},
+	[]string{"group", "kind", "action"},
+)
+
+// Reports the phase of Packages and ClusterPackages.
+var PackagePhase = newPhaseGauge(
+	prometheus.GaugeOpts{
+		Name: "package_operator_package_phase",
+		Help: "Phase of Packages, 1 for the current phase and 0 for all others.",
+	},
+	string(packagesv1alpha1.PackagePhasePending),
+	string(packagesv1alpha1.PackagePhaseAvailable),
+	string(packagesv1alpha1.PackagePhaseProgressing),
+	string(packagesv1alpha1.PackagePhaseUnpacking),
+	string(packagesv1alpha1.PackagePhaseNotReady),
+)
+
+// Reports the phase of ObjectSets and ClusterObjectSets.
+var ObjectSetPhase = newPhaseGauge(
+	prometheus.GaugeOpts{
+		Name: "package_operator_object_set_phase",
+		Help: "Phase of ObjectSets, 1 for the current phase and 0 for all others.",
+	},
+	string(packagesv1alpha1.ObjectSetPhasePending),
+	string(packagesv1alpha1.ObjectSetPhaseAvailable),
+	string(packagesv1alpha1.ObjectSetPhaseNotReady),
+	string(packagesv1alpha1.ObjectSetPhaseArchived),
+)
+
+// Reports the number of revisions (ObjectSets) of ObjectDeployments.
+var ObjectDeploymentRevisions = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "package_operator_object_deployment_revisions",
+		Help: "Number of ObjectSet revisions of an ObjectDeployment.",
+	},
+	[]string{"kind", "namespace", "name"},
+)
+
+// Observes how long reconciling the objects of a phase takes.
+// kind, namespace and name identify the ObjectSet or ObjectSetPhase owning the phase.
+var PhaseReconcileDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "package_operator_phase_reconcile_duration_seconds",
+		Help:    "Time taken to reconcile the objects of a phase.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+	},
+	[]string{"kind", "namespace", "name", "phase"},
+)
+
+// Counts objects failing their probes, each time a phase is reconciled.
+var PhaseProbeFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "package_operator_phase_probe_failures_total",
+		Help: "Number of times objects of a phase failed their probes.",
+	},
+	[]string{"kind", "namespace", "name", "phase"},
+)
+
+// Reports the number of objects managed by ObjectSets.
+var ObjectSetManagedObjects = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "package_operator_object_set_managed_objects",
+		Help: "Number of objects managed by an ObjectSet.",
+	},
+	[]string{"kind", "namespace", "name"},
+)
+
+// Observes the time from start to completion of successful unpack Jobs.
+var UnpackJobDuration = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "package_operator_unpack_job_duration_seconds",
+		Help:    "Time taken by unpack Jobs to complete successfully.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	},
+)
+
+// Counts failed unpack Jobs per Package.
+var UnpackJobFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "package_operator_unpack_job_failures_total",
+		Help: "Number of failed unpack Jobs.",
+	},
+	[]string{"namespace", "name"},
+)
+
+// Reports the informers started by DynamicWatchers.
+var DynamicWatcherInformers = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "package_operator_dynamic_watcher_informers",
+		Help: "Number of active informers started by dynamic watchers.",
+	},
+	[]string{"group", "kind"},
+)
+
+// Reports the owners keeping informers of DynamicWatchers alive.
+var DynamicWatcherOwnerReferences = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "package_operator_dynamic_watcher_owner_references",
+		Help: "Number of owners referencing informers of dynamic watchers.",
+	},
+	[]string{"group", "kind"},
+)
+
+// Reports the progress of Handovers.
+// state is one of "Found", "Updated" or "Available".
+var HandoverObjects = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "package_operator_handover_objects",
+		Help: "Number of objects found, updated and available of a Handover.",
+	},
+	[]string{"kind", "namespace", "name", "state"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ObjectDrift,
+		PackagePhase,
+		ObjectSetPhase,
+		ObjectDeploymentRevisions,
+		PhaseReconcileDuration,
+		PhaseProbeFailures,
+		ObjectSetManagedObjects,
+		UnpackJobDuration,
+		UnpackJobFailures,
+		DynamicWatcherInformers,
+		DynamicWatcherOwnerReferences,
+		HandoverObjects,
+	)
+}
+
+// Removes the series of phases owned by the given object.
+func DeletePhaseMetrics(kind, namespace, name string, phases ...string) {
+	for _, phase := range phases {
+		PhaseReconcileDuration.DeleteLabelValues(kind, namespace, name, phase)
+		PhaseProbeFailures.DeleteLabelValues(kind, namespace, name, phase)
+	}
+}
+
+// Gauge reporting the phase of objects.
+// Every known phase is reported, so phases that are not current drop to 0.
+type PhaseGauge struct {
+	*prometheus.GaugeVec
+	phases []string
+}
+
+func newPhaseGauge(opts prometheus.GaugeOpts, phases ...string) *PhaseGauge {
+	return &PhaseGauge{
+		GaugeVec: prometheus.NewGaugeVec(opts, []string{"kind", "namespace", "name", "phase"}),
+		phases:   phases,
+	}
+}
+
+// Reports the current phase of the given object.
+func (g *PhaseGauge) SetPhase(kind, namespace, name, phase string) {
+	for _, p := range g.phases {
+		var v float64
+		if p == phase {
+			v = 1
+		}
+		g.WithLabelValues(kind, namespace, name, p).Set(v)
+	}
+}
+
+// Removes all series of the given object.
+func (g *PhaseGauge) DeleteObject(kind, namespace, name string) {
+	for _, p := range g.phases {
+		g.DeleteLabelValues(kind, namespace, name, p)
+	}
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseGauge(t *testing.T) {
+	g := newPhaseGauge(prometheus.GaugeOpts{Name: "test_phase"}, "Available", "NotReady")
+
+	g.SetPhase("ObjectSet", "default", "test", "NotReady")
+	assert.Equal(t, 2, testutil.CollectAndCount(g))
+	assert.Equal(t, float64(0), testutil.ToFloat64(g.WithLabelValues("ObjectSet", "default", "test", "Available")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(g.WithLabelValues("ObjectSet", "default", "test", "NotReady")))
+
+	g.SetPhase("ObjectSet", "default", "test", "Available")
+	assert.Equal(t, float64(1), testutil.ToFloat64(g.WithLabelValues("ObjectSet", "default", "test", "Available")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(g.WithLabelValues("ObjectSet", "default", "test", "NotReady")))
+
+	g.DeleteObject("ObjectSet", "default", "test")
+	assert.Equal(t, 0, testutil.CollectAndCount(g))
+}
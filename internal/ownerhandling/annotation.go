@@ -96,6 +96,32 @@ func (s *OwnerStrategyAnnotation) ReleaseController(obj metav1.Object) {
 	s.setOwnerReferences(obj, ownerRefs)
 }
 
+func (s *OwnerStrategyAnnotation) GetController(obj metav1.Object) (metav1.OwnerReference, bool) {
+	for _, ownerRef := range s.getOwnerReferences(obj) {
+		if ownerRef.Controller != nil && *ownerRef.Controller {
+			return metav1.OwnerReference{
+				APIVersion: ownerRef.APIVersion,
+				Kind:       ownerRef.Kind,
+				Name:       ownerRef.Name,
+				UID:        ownerRef.UID,
+				Controller: ownerRef.Controller,
+			}, true
+		}
+	}
+	return metav1.OwnerReference{}, false
+}
+
+func (s *OwnerStrategyAnnotation) RemoveOwner(owner, obj metav1.Object) {
+	ownerRefs := s.getOwnerReferences(obj)
+	for i := range ownerRefs {
+		if ownerRefs[i].UID == owner.GetUID() {
+			ownerRefs = append(ownerRefs[:i], ownerRefs[i+1:]...)
+			break
+		}
+	}
+	s.setOwnerReferences(obj, ownerRefs)
+}
+
 func (s *OwnerStrategyAnnotation) getOwnerReferences(obj metav1.Object) []annotationOwnerRef {
 	annotations := obj.GetAnnotations()
 	if annotations == nil {
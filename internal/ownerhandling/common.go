@@ -10,6 +10,8 @@ import (
 type ownerStrategy interface {
 	IsOwner(owner, obj metav1.Object) bool
 	ReleaseController(obj metav1.Object)
+	GetController(obj metav1.Object) (metav1.OwnerReference, bool)
+	RemoveOwner(owner, obj metav1.Object)
 	SetControllerReference(owner, obj metav1.Object, scheme *runtime.Scheme) error
 	EnqueueRequestForOwner(ownerType client.Object, isController bool) handler.EventHandler
 }
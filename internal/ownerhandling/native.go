@@ -30,6 +30,25 @@ func (s *OwnerStrategyNative) ReleaseController(obj metav1.Object) {
 	obj.SetOwnerReferences(ownerRefs)
 }
 
+func (s *OwnerStrategyNative) GetController(obj metav1.Object) (metav1.OwnerReference, bool) {
+	ownerRef := metav1.GetControllerOf(obj)
+	if ownerRef == nil {
+		return metav1.OwnerReference{}, false
+	}
+	return *ownerRef, true
+}
+
+func (s *OwnerStrategyNative) RemoveOwner(owner, obj metav1.Object) {
+	ownerRefs := obj.GetOwnerReferences()
+	for i := range ownerRefs {
+		if ownerRefs[i].UID == owner.GetUID() {
+			ownerRefs = append(ownerRefs[:i], ownerRefs[i+1:]...)
+			break
+		}
+	}
+	obj.SetOwnerReferences(ownerRefs)
+}
+
 func (s *OwnerStrategyNative) SetControllerReference(owner, obj metav1.Object, scheme *runtime.Scheme) error {
 	return controllerutil.SetControllerReference(owner, obj, scheme)
 }
@@ -0,0 +1,118 @@
+package probe
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Upper bound of the runtime cost of a single CEL rule evaluation,
+// to protect the controller from expensive expressions.
+const celCostLimit = 1000000
+
+// Evaluates a CEL rule against the object, which is available as "self".
+type CELProbe struct {
+	Rule, Message string
+	program       cel.Program
+}
+
+var _ Interface = (*CELProbe)(nil)
+
+// Compiles the given CEL rule into a new CELProbe.
+// Compiled programs are cached by rule, as probes are parsed on every reconcile.
+func NewCELProbe(rule, message string) (*CELProbe, error) {
+	program, err := celPrograms.get(rule)
+	if err != nil {
+		return nil, err
+	}
+	return &CELProbe{
+		Rule:    rule,
+		Message: message,
+		program: program,
+	}, nil
+}
+
+// Upper bound of cached programs, the cache is reset when exceeded,
+// so rules of deleted objects don't accumulate forever.
+const celProgramCacheSize = 1000
+
+var celPrograms = &celProgramCache{programs: map[string]celProgramResult{}}
+
+// Compiled programs, or the compilation error, by CEL rule.
+// cel.Program is safe for concurrent use.
+type celProgramCache struct {
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+
+	mux      sync.Mutex
+	programs map[string]celProgramResult
+}
+
+type celProgramResult struct {
+	program cel.Program
+	err     error
+}
+
+func (c *celProgramCache) get(rule string) (cel.Program, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if res, ok := c.programs[rule]; ok {
+		return res.program, res.err
+	}
+	program, err := c.compile(rule)
+	if len(c.programs) >= celProgramCacheSize {
+		c.programs = map[string]celProgramResult{}
+	}
+	c.programs[rule] = celProgramResult{program: program, err: err}
+	return program, err
+}
+
+func (c *celProgramCache) compile(rule string) (cel.Program, error) {
+	c.envOnce.Do(func() {
+		c.env, c.envErr = cel.NewEnv(
+			cel.Variable("self", cel.DynType),
+		)
+	})
+	if c.envErr != nil {
+		return nil, fmt.Errorf("CEL environment: %w", c.envErr)
+	}
+
+	ast, issues := c.env.Compile(rule)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("compiling CEL rule %q: %w", rule, issues.Err())
+	}
+	if !ast.OutputType().IsAssignableType(cel.BoolType) {
+		return nil, fmt.Errorf(
+			"CEL rule %q must evaluate to bool, not %s", rule, ast.OutputType())
+	}
+
+	program, err := c.env.Program(ast, cel.CostLimit(celCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("CEL program for rule %q: %w", rule, err)
+	}
+	return program, nil
+}
+
+func (cp *CELProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	val, _, err := cp.program.Eval(map[string]interface{}{
+		"self": obj.Object,
+	})
+	if err != nil {
+		return false, fmt.Sprintf("CEL rule %q: %v", cp.Rule, err)
+	}
+
+	if success, ok := val.Value().(bool); !ok {
+		return false, fmt.Sprintf("CEL rule %q: returned %s, not bool", cp.Rule, val.Type().TypeName())
+	} else if success {
+		return true, ""
+	}
+
+	if len(cp.Message) > 0 {
+		return false, cp.Message
+	}
+	return false, fmt.Sprintf("CEL rule %q failed", cp.Rule)
+}
@@ -0,0 +1,89 @@
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCELProbe(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+			},
+			"status": map[string]interface{}{
+				"readyReplicas": int64(2),
+				"phase":         "Running",
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		rule, message   string
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name:            "succeeds",
+			rule:            `self.status.phase in ["Running", "Succeeded"]`,
+			expectedSuccess: true,
+		},
+		{
+			name:            "fails with message",
+			rule:            `self.status.readyReplicas >= self.spec.replicas`,
+			message:         "not enough replicas",
+			expectedMessage: "not enough replicas",
+		},
+		{
+			name:            "fails without message",
+			rule:            `self.status.phase == "Succeeded"`,
+			expectedMessage: `CEL rule "self.status.phase == \"Succeeded\"" failed`,
+		},
+		{
+			name:            "missing field",
+			rule:            `self.status.availableReplicas > 0`,
+			expectedMessage: `CEL rule "self.status.availableReplicas > 0": no such key: availableReplicas`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := NewCELProbe(test.rule, test.message)
+			require.NoError(t, err)
+
+			success, message := p.Probe(obj)
+			assert.Equal(t, test.expectedSuccess, success)
+			assert.Equal(t, test.expectedMessage, message)
+		})
+	}
+}
+
+func TestNewCELProbe_invalid(t *testing.T) {
+	_, err := NewCELProbe(`self.status.`, "")
+	assert.Error(t, err)
+
+	_, err = NewCELProbe(`1 + 1`, "")
+	assert.EqualError(t, err, `CEL rule "1 + 1" must evaluate to bool, not int`)
+}
+
+func TestNewCELProbe_cached(t *testing.T) {
+	rule := `self.metadata.name == "cached"`
+	p1, err := NewCELProbe(rule, "a")
+	require.NoError(t, err)
+	p2, err := NewCELProbe(rule, "b")
+	require.NoError(t, err)
+
+	// the program is compiled once and shared, messages are not.
+	assert.Equal(t, p1.program, p2.program)
+	assert.Equal(t, "a", p1.Message)
+	assert.Equal(t, "b", p2.Message)
+
+	_, err = NewCELProbe(`1 + 1`, "")
+	assert.Error(t, err)
+	_, err = NewCELProbe(`1 + 1`, "")
+	assert.EqualError(t, err, `CEL rule "1 + 1" must evaluate to bool, not int`)
+}
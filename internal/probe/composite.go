@@ -0,0 +1,55 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Succeeds if at least one of the nested probes succeeds.
+type AnyOfProbe []Interface
+
+var _ Interface = (AnyOfProbe)(nil)
+
+func (p AnyOfProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	messages := make([]string, 0, len(p))
+	for _, probe := range p {
+		success, message := probe.Probe(obj)
+		if success {
+			return true, ""
+		}
+		messages = append(messages, message)
+	}
+	return false, fmt.Sprintf("any of: [%s]", strings.Join(messages, " | "))
+}
+
+// Succeeds if all of the nested probes succeed.
+// Other than ProbeList, failure messages are grouped,
+// so they stay readable when nested in other probes.
+type AllOfProbe []Interface
+
+var _ Interface = (AllOfProbe)(nil)
+
+func (p AllOfProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	if success, message := ProbeList(p).Probe(obj); !success {
+		return false, fmt.Sprintf("all of: [%s]", message)
+	}
+	return true, ""
+}
+
+// Succeeds if the nested probe fails.
+type NotProbe struct {
+	Interface
+	// Describes the nested probe in failure messages.
+	Description string
+}
+
+var _ Interface = (*NotProbe)(nil)
+
+func (p *NotProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	if success, _ := p.Interface.Probe(obj); success {
+		return false, fmt.Sprintf("not: %s succeeded", p.Description)
+	}
+	return true, ""
+}
@@ -0,0 +1,134 @@
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestCompositeProbes(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":   "Available",
+						"status": "False",
+					},
+					map[string]interface{}{
+						"type":   "Progressing",
+						"status": "False",
+						"reason": "NewReplicaSetAvailable",
+					},
+					map[string]interface{}{
+						"type":   "Degraded",
+						"status": "True",
+					},
+				},
+			},
+		},
+	}
+
+	conditionWithReason := func(condType, status, reason string) packagesv1alpha1.NestedProbe {
+		return packagesv1alpha1.NestedProbe{Probe: packagesv1alpha1.Probe{
+			Type: packagesv1alpha1.ProbeCondition,
+			Condition: &packagesv1alpha1.ProbeConditionSpec{
+				Type: condType, Status: status, Reason: reason,
+			},
+		}}
+	}
+	condition := func(condType, status string) packagesv1alpha1.NestedProbe {
+		return conditionWithReason(condType, status, "")
+	}
+
+	tests := []struct {
+		name            string
+		spec            packagesv1alpha1.Probe
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name: "anyOf succeeds",
+			spec: packagesv1alpha1.Probe{
+				Type: packagesv1alpha1.ProbeAnyOf,
+				AnyOf: []packagesv1alpha1.NestedProbe{
+					condition("Available", "True"),
+					condition("Progressing", "False"),
+				},
+			},
+			expectedSuccess: true,
+		},
+		{
+			name: "anyOf succeeds with reason",
+			spec: packagesv1alpha1.Probe{
+				Type: packagesv1alpha1.ProbeAnyOf,
+				AnyOf: []packagesv1alpha1.NestedProbe{
+					condition("Available", "True"),
+					conditionWithReason("Progressing", "False", "NewReplicaSetAvailable"),
+				},
+			},
+			expectedSuccess: true,
+		},
+		{
+			name: "wrong reason",
+			spec: packagesv1alpha1.Probe{
+				Type: packagesv1alpha1.ProbeAllOf,
+				AllOf: []packagesv1alpha1.NestedProbe{
+					conditionWithReason("Progressing", "False", "ProgressDeadlineExceeded"),
+				},
+			},
+			expectedMessage: `all of: [condition "Progressing" == "False" with reason "ProgressDeadlineExceeded": wrong reason]`,
+		},
+		{
+			name: "nested failure",
+			spec: packagesv1alpha1.Probe{
+				Type: packagesv1alpha1.ProbeAnyOf,
+				AnyOf: []packagesv1alpha1.NestedProbe{
+					condition("Available", "True"),
+					{Probe: packagesv1alpha1.Probe{
+						Type: packagesv1alpha1.ProbeAllOf,
+						AllOf: []packagesv1alpha1.NestedProbe{
+							condition("Progressing", "False"),
+							{Probe: packagesv1alpha1.Probe{
+								Type: packagesv1alpha1.ProbeNot,
+								Not: &packagesv1alpha1.NestedProbe{
+									Probe: condition("Degraded", "True").Probe,
+								},
+							}},
+						},
+					}},
+				},
+			},
+			expectedMessage: `any of: [condition "Available" == "True": wrong status | ` +
+				`all of: [not: condition "Degraded" == "True" succeeded]]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := parseProbe(test.spec)
+			require.NoError(t, err)
+
+			success, message := p.Probe(obj)
+			assert.Equal(t, test.expectedSuccess, success)
+			assert.Equal(t, test.expectedMessage, message)
+		})
+	}
+}
+
+func TestParse_nestedInvalid(t *testing.T) {
+	_, err := Parse([]packagesv1alpha1.Probe{
+		{
+			Type: packagesv1alpha1.ProbeAnyOf,
+			AnyOf: []packagesv1alpha1.NestedProbe{
+				{Probe: packagesv1alpha1.Probe{Type: packagesv1alpha1.ProbeCondition}},
+			},
+		},
+	})
+	assert.EqualError(t, err,
+		`probes[0]: anyOf: probes[0]: probe of type "Condition" requires .condition to be set`)
+}
@@ -0,0 +1,118 @@
+package probe
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+)
+
+// Built-in readiness probes for well-known Kubernetes kinds.
+var defaultProbes = map[schema.GroupKind]Interface{
+	{Group: "apps", Kind: "Deployment"}: &CurrentGenerationProbe{
+		Interface: ProbeList{
+			&ConditionProbe{Type: "Available", Status: "True"},
+			&replicasEqualProbe{FieldA: ".status.updatedReplicas", FieldB: ".spec.replicas"},
+			&replicasEqualProbe{FieldA: ".status.availableReplicas", FieldB: ".spec.replicas"},
+			// no replicas of older ReplicaSets still around
+			&replicasEqualProbe{FieldA: ".status.replicas", FieldB: ".status.updatedReplicas"},
+		},
+	},
+	{Group: "apps", Kind: "StatefulSet"}: &CurrentGenerationProbe{
+		Interface: ProbeList{
+			&replicasEqualProbe{FieldA: ".status.readyReplicas", FieldB: ".spec.replicas"},
+			&replicasEqualProbe{FieldA: ".status.updatedReplicas", FieldB: ".spec.replicas"},
+			&FieldsEqualProbe{FieldA: ".status.currentRevision", FieldB: ".status.updateRevision"},
+		},
+	},
+	{Group: "apps", Kind: "DaemonSet"}: &CurrentGenerationProbe{
+		Interface: ProbeList{
+			&replicasEqualProbe{FieldA: ".status.numberAvailable", FieldB: ".status.desiredNumberScheduled"},
+			&replicasEqualProbe{FieldA: ".status.updatedNumberScheduled", FieldB: ".status.desiredNumberScheduled"},
+		},
+	},
+	{Group: "batch", Kind: "Job"}: &CurrentGenerationProbe{
+		Interface: &ConditionProbe{Type: "Complete", Status: "True"},
+	},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: &CurrentGenerationProbe{
+		Interface: &ConditionProbe{Type: "Established", Status: "True"},
+	},
+	{Kind: "PersistentVolumeClaim"}: &FieldValueProbe{
+		Field: ".status.phase", Equals: pointer.StringPtr("Bound"),
+	},
+	{Kind: "Service"}: &serviceLoadBalancerProbe{},
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}: &CurrentGenerationProbe{
+		Interface: &ConditionProbe{Type: "Available", Status: "True"},
+	},
+}
+
+// DefaultProbe executes the built-in probe for the kind of the probed object.
+// Objects of kinds without a built-in probe always succeed.
+type DefaultProbe struct{}
+
+var _ Interface = (*DefaultProbe)(nil)
+
+func (dp *DefaultProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	probe, ok := defaultProbes[obj.GroupVersionKind().GroupKind()]
+	if !ok {
+		return true, ""
+	}
+	return probe.Probe(obj)
+}
+
+// Compares two replica counts, treating missing fields as 0,
+// because replica counts are omitted from .status when they are 0.
+type replicasEqualProbe struct {
+	FieldA, FieldB string
+}
+
+var _ Interface = (*replicasEqualProbe)(nil)
+
+func (re *replicasEqualProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	fieldAVal, err := nestedReplicas(obj, re.FieldA)
+	if err != nil {
+		return false, err.Error()
+	}
+	fieldBVal, err := nestedReplicas(obj, re.FieldB)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if fieldAVal != fieldBVal {
+		return false, fmt.Sprintf("%q == %q: %d != %d", re.FieldA, re.FieldB, fieldAVal, fieldBVal)
+	}
+	return true, ""
+}
+
+func nestedReplicas(obj *unstructured.Unstructured, field string) (int64, error) {
+	fieldPath := strings.Split(strings.Trim(field, "."), ".")
+	replicas, ok, err := unstructured.NestedInt64(obj.Object, fieldPath...)
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", field, err)
+	}
+	if !ok && field == ".spec.replicas" {
+		// defaulted by the API server
+		return 1, nil
+	}
+	return replicas, nil
+}
+
+// Checks that Services of type LoadBalancer have an ingress point assigned.
+type serviceLoadBalancerProbe struct{}
+
+var _ Interface = (*serviceLoadBalancerProbe)(nil)
+
+func (sp *serviceLoadBalancerProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	if serviceType != "LoadBalancer" {
+		return true, ""
+	}
+
+	ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	if len(ingress) == 0 {
+		return false, "no LoadBalancer ingress assigned"
+	}
+	return true, ""
+}
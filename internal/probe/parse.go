@@ -1,43 +1,163 @@
 package probe
 
 import (
+	"fmt"
+	"regexp"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
 	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
 )
 
-func Parse(probeSpecs []packagesv1alpha1.Probe) Interface {
-	var probeList ProbeList
-	for _, probeSpec := range probeSpecs {
-		// main probe type
-		var probe Interface
-		switch probeSpec.Type {
-		case packagesv1alpha1.ProbeCondition:
-			if probeSpec.Condition == nil {
-				continue
-			}
+// Parses the given probe specifications into a ProbeList.
+// Returns an aggregate of all validation errors encountered.
+func Parse(probeSpecs []packagesv1alpha1.Probe) (Interface, error) {
+	probeList, err := parseList(probeSpecs)
+	if err != nil {
+		return nil, err
+	}
+	return probeList, nil
+}
 
-			probe = &ConditionProbe{
-				Type:   probeSpec.Condition.Type,
-				Status: probeSpec.Condition.Status,
-			}
+func parseList(probeSpecs []packagesv1alpha1.Probe) (ProbeList, error) {
+	var (
+		probeList ProbeList
+		errs      []error
+	)
+	for i, probeSpec := range probeSpecs {
+		probe, err := parseProbe(probeSpec)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("probes[%d]: %w", i, err))
+			continue
+		}
+		probeList = append(probeList, probe)
+	}
+	if len(errs) > 0 {
+		return nil, utilerrors.NewAggregate(errs)
+	}
 
-		case packagesv1alpha1.ProbeFieldsEqual:
-			if probeSpec.FieldsEqual == nil {
-				continue
-			}
+	return probeList, nil
+}
+
+func parseProbe(probeSpec packagesv1alpha1.Probe) (Interface, error) {
+	switch probeSpec.Type {
+	case packagesv1alpha1.ProbeCondition:
+		if probeSpec.Condition == nil {
+			return nil, missingProbeSpecError(probeSpec.Type, "condition")
+		}
+
+		return &ConditionProbe{
+			Type:   probeSpec.Condition.Type,
+			Status: probeSpec.Condition.Status,
+			Reason: probeSpec.Condition.Reason,
+		}, nil
+
+	case packagesv1alpha1.ProbeFieldsEqual:
+		if probeSpec.FieldsEqual == nil {
+			return nil, missingProbeSpecError(probeSpec.Type, "fieldsEqual")
+		}
 
-			probe = &FieldsEqualProbe{
-				FieldA: probeSpec.FieldsEqual.FieldA,
-				FieldB: probeSpec.FieldsEqual.FieldB,
+		return &FieldsEqualProbe{
+			FieldA: probeSpec.FieldsEqual.FieldA,
+			FieldB: probeSpec.FieldsEqual.FieldB,
+		}, nil
+
+	case packagesv1alpha1.ProbeFieldValue:
+		if probeSpec.FieldValue == nil {
+			return nil, missingProbeSpecError(probeSpec.Type, "fieldValue")
+		}
+
+		spec := probeSpec.FieldValue
+		fieldValueProbe := &FieldValueProbe{
+			Field:              spec.Field,
+			Equals:             spec.Equals,
+			OneOf:              spec.OneOf,
+			GreaterThan:        spec.GreaterThan,
+			GreaterThanOrEqual: spec.GreaterThanOrEqual,
+			LessThan:           spec.LessThan,
+			LessThanOrEqual:    spec.LessThanOrEqual,
+		}
+		if len(spec.Regex) > 0 {
+			regex, err := regexp.Compile(spec.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", spec.Regex, err)
 			}
+			fieldValueProbe.Regex = regex
+		}
+		return fieldValueProbe, nil
 
-		default:
-			// Unknown probe type
-			panic("unknown probe type")
-			// continue
+	case packagesv1alpha1.ProbeCEL:
+		if probeSpec.CEL == nil {
+			return nil, missingProbeSpecError(probeSpec.Type, "cel")
 		}
 
-		probeList = append(probeList, probe)
+		return NewCELProbe(probeSpec.CEL.Rule, probeSpec.CEL.Message)
+
+	case packagesv1alpha1.ProbeAnyOf:
+		if len(probeSpec.AnyOf) == 0 {
+			return nil, missingProbeSpecError(probeSpec.Type, "anyOf")
+		}
+
+		probeList, err := parseNested(probeSpec.AnyOf)
+		if err != nil {
+			return nil, fmt.Errorf("anyOf: %w", err)
+		}
+		return AnyOfProbe(probeList), nil
+
+	case packagesv1alpha1.ProbeAllOf:
+		if len(probeSpec.AllOf) == 0 {
+			return nil, missingProbeSpecError(probeSpec.Type, "allOf")
+		}
+
+		probeList, err := parseNested(probeSpec.AllOf)
+		if err != nil {
+			return nil, fmt.Errorf("allOf: %w", err)
+		}
+		return AllOfProbe(probeList), nil
+
+	case packagesv1alpha1.ProbeNot:
+		if probeSpec.Not == nil {
+			return nil, missingProbeSpecError(probeSpec.Type, "not")
+		}
+
+		probe, err := parseProbe(probeSpec.Not.Probe)
+		if err != nil {
+			return nil, fmt.Errorf("not: %w", err)
+		}
+		return &NotProbe{
+			Interface:   probe,
+			Description: describe(probeSpec.Not.Probe),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown probe type %q", probeSpec.Type)
+}
+
+func parseNested(nestedSpecs []packagesv1alpha1.NestedProbe) (ProbeList, error) {
+	probeSpecs := make([]packagesv1alpha1.Probe, len(nestedSpecs))
+	for i := range nestedSpecs {
+		probeSpecs[i] = nestedSpecs[i].Probe
 	}
+	return parseList(probeSpecs)
+}
+
+// Short human readable description of a probe for failure messages.
+func describe(probeSpec packagesv1alpha1.Probe) string {
+	switch {
+	case probeSpec.Type == packagesv1alpha1.ProbeCondition && probeSpec.Condition != nil:
+		return (&ConditionProbe{
+			Type:   probeSpec.Condition.Type,
+			Status: probeSpec.Condition.Status,
+			Reason: probeSpec.Condition.Reason,
+		}).describe()
+	case probeSpec.Type == packagesv1alpha1.ProbeFieldsEqual && probeSpec.FieldsEqual != nil:
+		return fmt.Sprintf("%q == %q", probeSpec.FieldsEqual.FieldA, probeSpec.FieldsEqual.FieldB)
+	case probeSpec.Type == packagesv1alpha1.ProbeCEL && probeSpec.CEL != nil:
+		return fmt.Sprintf("CEL rule %q", probeSpec.CEL.Rule)
+	}
+	return fmt.Sprintf("%s probe", probeSpec.Type)
+}
 
-	return probeList
+func missingProbeSpecError(probeType packagesv1alpha1.ProbeType, field string) error {
+	return fmt.Errorf("probe of type %q requires .%s to be set", probeType, field)
 }
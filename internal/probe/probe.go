@@ -2,6 +2,8 @@ package probe
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -30,6 +32,8 @@ func (p ProbeList) Probe(obj *unstructured.Unstructured) (success bool, message
 // Checks if the objects condition is set and in a certain status.
 type ConditionProbe struct {
 	Type, Status string
+	// Optional Reason the condition has to report.
+	Reason string
 }
 
 var _ Interface = (*ConditionProbe)(nil)
@@ -40,7 +44,7 @@ func (cp *ConditionProbe) Probe(obj *unstructured.Unstructured) (success bool, m
 			return
 		}
 		// add probed condition type and status as context to error message.
-		message = fmt.Sprintf("condition %q == %q: %s", cp.Type, cp.Status, message)
+		message = fmt.Sprintf("%s: %s", cp.describe(), message)
 	}()
 
 	conditions, exist, err := unstructured.
@@ -68,15 +72,24 @@ func (cp *ConditionProbe) Probe(obj *unstructured.Unstructured) (success bool, m
 			return false, "outdated"
 		}
 
-		if cond["status"] == cp.Status {
-			return true, ""
-		} else {
+		if cond["status"] != cp.Status {
 			return false, "wrong status"
 		}
+		if len(cp.Reason) > 0 && cond["reason"] != cp.Reason {
+			return false, "wrong reason"
+		}
+		return true, ""
 	}
 	return false, "not reported"
 }
 
+func (cp *ConditionProbe) describe() string {
+	if len(cp.Reason) > 0 {
+		return fmt.Sprintf("condition %q == %q with reason %q", cp.Type, cp.Status, cp.Reason)
+	}
+	return fmt.Sprintf("condition %q == %q", cp.Type, cp.Status)
+}
+
 // Checks if the values of the fields under the given json paths are equal.
 type FieldsEqualProbe struct {
 	FieldA, FieldB string
@@ -108,6 +121,84 @@ func (fe *FieldsEqualProbe) Probe(obj *unstructured.Unstructured) (success bool,
 	return equality.Semantic.DeepEqual(fieldAVal, fieldBVal), fmt.Sprintf("%s != %s", fieldAVal, fieldBVal)
 }
 
+// Checks the value of the field under the given json path.
+// All checks that are set have to succeed.
+type FieldValueProbe struct {
+	Field string
+	// Value has to be equal.
+	Equals *string
+	// Value has to be equal to one of the list entries.
+	OneOf []string
+	// Value has to match the regular expression.
+	Regex *regexp.Regexp
+	// Numeric bounds.
+	GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual *int64
+}
+
+var _ Interface = (*FieldValueProbe)(nil)
+
+func (fv *FieldValueProbe) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	fieldPath := strings.Split(strings.Trim(fv.Field, "."), ".")
+
+	defer func() {
+		if success {
+			return
+		}
+		// add probed field path as context to error message.
+		message = fmt.Sprintf("%q: %s", fv.Field, message)
+	}()
+
+	fieldVal, ok, err := unstructured.NestedFieldNoCopy(obj.Object, fieldPath...)
+	if err != nil || !ok {
+		return false, "missing"
+	}
+	value := fmt.Sprint(fieldVal)
+
+	if fv.Equals != nil && value != *fv.Equals {
+		return false, fmt.Sprintf("%q != %q", value, *fv.Equals)
+	}
+
+	if len(fv.OneOf) > 0 {
+		var found bool
+		for _, allowed := range fv.OneOf {
+			if value == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, fmt.Sprintf("%q not one of %q", value, fv.OneOf)
+		}
+	}
+
+	if fv.Regex != nil && !fv.Regex.MatchString(value) {
+		return false, fmt.Sprintf("%q does not match %q", value, fv.Regex.String())
+	}
+
+	if fv.GreaterThan == nil && fv.GreaterThanOrEqual == nil &&
+		fv.LessThan == nil && fv.LessThanOrEqual == nil {
+		return true, ""
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, fmt.Sprintf("%q is not a number", value)
+	}
+	if fv.GreaterThan != nil && !(number > float64(*fv.GreaterThan)) {
+		return false, fmt.Sprintf("%s <= %d", value, *fv.GreaterThan)
+	}
+	if fv.GreaterThanOrEqual != nil && !(number >= float64(*fv.GreaterThanOrEqual)) {
+		return false, fmt.Sprintf("%s < %d", value, *fv.GreaterThanOrEqual)
+	}
+	if fv.LessThan != nil && !(number < float64(*fv.LessThan)) {
+		return false, fmt.Sprintf("%s >= %d", value, *fv.LessThan)
+	}
+	if fv.LessThanOrEqual != nil && !(number <= float64(*fv.LessThanOrEqual)) {
+		return false, fmt.Sprintf("%s > %d", value, *fv.LessThanOrEqual)
+	}
+	return true, ""
+}
+
 // CurrentGenerationProbe ensures that the objects status is up to date with the objects generation.
 // Requires the probed object to have a .status.observedGeneration property.
 type CurrentGenerationProbe struct {
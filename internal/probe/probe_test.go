@@ -0,0 +1,153 @@
+package probe
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	packagesv1alpha1 "github.com/thetechnick/package-operator/apis/packages/v1alpha1"
+)
+
+func TestFieldValueProbe(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"state":         "Ready",
+				"health":        "Yellow",
+				"readyReplicas": int64(2),
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		probe           *FieldValueProbe
+		expectedSuccess bool
+		expectedMessage string
+	}{
+		{
+			name: "equals",
+			probe: &FieldValueProbe{
+				Field: ".status.state", Equals: pointer.String("Ready"),
+			},
+			expectedSuccess: true,
+		},
+		{
+			name: "not equals",
+			probe: &FieldValueProbe{
+				Field: ".status.state", Equals: pointer.String("Failed"),
+			},
+			expectedMessage: `".status.state": "Ready" != "Failed"`,
+		},
+		{
+			name: "missing",
+			probe: &FieldValueProbe{
+				Field: ".status.phase", Equals: pointer.String("Ready"),
+			},
+			expectedMessage: `".status.phase": missing`,
+		},
+		{
+			name: "not one of",
+			probe: &FieldValueProbe{
+				Field: ".status.health", OneOf: []string{"Green", "Blue"},
+			},
+			expectedMessage: `".status.health": "Yellow" not one of ["Green" "Blue"]`,
+		},
+		{
+			name: "regex",
+			probe: &FieldValueProbe{
+				Field: ".status.health", Regex: regexp.MustCompile("^(Green|Yellow)$"),
+			},
+			expectedSuccess: true,
+		},
+		{
+			name: "numeric bounds",
+			probe: &FieldValueProbe{
+				Field:              ".status.readyReplicas",
+				GreaterThanOrEqual: pointer.Int64(1),
+				LessThan:           pointer.Int64(3),
+			},
+			expectedSuccess: true,
+		},
+		{
+			name: "numeric bounds violated",
+			probe: &FieldValueProbe{
+				Field:              ".status.readyReplicas",
+				GreaterThanOrEqual: pointer.Int64(3),
+			},
+			expectedMessage: `".status.readyReplicas": 2 < 3`,
+		},
+		{
+			name: "not a number",
+			probe: &FieldValueProbe{
+				Field:       ".status.state",
+				GreaterThan: pointer.Int64(3),
+			},
+			expectedMessage: `".status.state": "Ready" is not a number`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			success, message := test.probe.Probe(obj)
+			assert.Equal(t, test.expectedSuccess, success)
+			assert.Equal(t, test.expectedMessage, message)
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	_, err := Parse([]packagesv1alpha1.Probe{
+		{Type: packagesv1alpha1.ProbeCondition},
+		{Type: "Unknown"},
+		{
+			Type: packagesv1alpha1.ProbeCEL,
+			CEL:  &packagesv1alpha1.ProbeCELSpec{Rule: "1 + 1"},
+		},
+	})
+	assert.EqualError(t, err, `[`+
+		`probes[0]: probe of type "Condition" requires .condition to be set, `+
+		`probes[1]: unknown probe type "Unknown", `+
+		`probes[2]: CEL rule "1 + 1" must evaluate to bool, not int]`)
+}
+
+func TestDefaultProbe_deployment(t *testing.T) {
+	deploy := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"generation": int64(2),
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(2),
+			},
+			"status": map[string]interface{}{
+				"observedGeneration": int64(2),
+				"replicas":           int64(3),
+				"updatedReplicas":    int64(2),
+				"availableReplicas":  int64(2),
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Available", "status": "True"},
+				},
+			},
+		},
+	}
+
+	p := &DefaultProbe{}
+	success, message := p.Probe(deploy)
+	assert.False(t, success)
+	assert.Equal(t, `".status.replicas" == ".status.updatedReplicas": 3 != 2`, message)
+
+	deploy.Object["status"].(map[string]interface{})["replicas"] = int64(2)
+	success, _ = p.Probe(deploy)
+	assert.True(t, success)
+
+	deploy.SetGeneration(3)
+	success, message = p.Probe(deploy)
+	assert.False(t, success)
+	assert.Equal(t, ".status outdated", message)
+}
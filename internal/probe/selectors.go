@@ -1,7 +1,11 @@
 package probe
 
 import (
+	"fmt"
+	"path"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -21,3 +25,54 @@ func (kp *KindSelector) Probe(obj *unstructured.Unstructured) (success bool, mes
 	// don't probe stuff that does not match
 	return true, ""
 }
+
+// LabelSelector wraps a Probe object and only executes the probe when the probed object matches the label selector.
+type LabelSelector struct {
+	Interface
+	labels.Selector
+}
+
+func (lp *LabelSelector) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	if lp.Selector.Matches(labels.Set(obj.GetLabels())) {
+		return lp.Interface.Probe(obj)
+	}
+
+	// don't probe stuff that does not match
+	return true, ""
+}
+
+// NameSelector wraps a Probe object and only executes the probe when the probed object has the right Namespace and Name.
+// Namespace and Name support glob patterns, an empty Namespace matches all namespaces.
+type NameSelector struct {
+	Interface
+	Namespace, Name string
+}
+
+// Creates a new NameSelector, validating the given patterns.
+func NewNameSelector(probe Interface, namespace, name string) (*NameSelector, error) {
+	for _, pattern := range []string{namespace, name} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return &NameSelector{
+		Interface: probe,
+		Namespace: namespace,
+		Name:      name,
+	}, nil
+}
+
+func (np *NameSelector) Probe(obj *unstructured.Unstructured) (success bool, message string) {
+	// patterns are validated in NewNameSelector.
+	nameMatches, _ := path.Match(np.Name, obj.GetName())
+	namespaceMatches := len(np.Namespace) == 0
+	if !namespaceMatches {
+		namespaceMatches, _ = path.Match(np.Namespace, obj.GetNamespace())
+	}
+	if nameMatches && namespaceMatches {
+		return np.Interface.Probe(obj)
+	}
+
+	// don't probe stuff that does not match
+	return true, ""
+}
@@ -0,0 +1,107 @@
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Probe that always fails, to tell whether a selector executed it.
+type failingProbe struct{}
+
+func (failingProbe) Probe(obj *unstructured.Unstructured) (bool, string) {
+	return false, "probed"
+}
+
+func selectorTestObject() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("apps/v1")
+	obj.SetKind("Deployment")
+	obj.SetName("web-frontend")
+	obj.SetNamespace("prod")
+	obj.SetLabels(map[string]string{"app": "web"})
+	return obj
+}
+
+func TestSelectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		probe   Interface
+		matches bool
+	}{
+		{
+			name: "kind",
+			probe: &KindSelector{
+				Interface: failingProbe{},
+				GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			},
+			matches: true,
+		},
+		{
+			name: "kind of other group",
+			probe: &KindSelector{
+				Interface: failingProbe{},
+				GroupKind: schema.GroupKind{Kind: "Deployment"},
+			},
+			matches: false,
+		},
+		{
+			name: "label",
+			probe: &LabelSelector{
+				Interface: failingProbe{},
+				Selector:  labels.SelectorFromSet(labels.Set{"app": "web"}),
+			},
+			matches: true,
+		},
+		{
+			name: "other label",
+			probe: &LabelSelector{
+				Interface: failingProbe{},
+				Selector:  labels.SelectorFromSet(labels.Set{"app": "db"}),
+			},
+			matches: false,
+		},
+		{
+			name:    "name in any namespace",
+			probe:   &NameSelector{Interface: failingProbe{}, Name: "web-frontend"},
+			matches: true,
+		},
+		{
+			name:    "name glob in namespace glob",
+			probe:   &NameSelector{Interface: failingProbe{}, Namespace: "pro*", Name: "web-*"},
+			matches: true,
+		},
+		{
+			name:    "name in other namespace",
+			probe:   &NameSelector{Interface: failingProbe{}, Namespace: "dev", Name: "web-frontend"},
+			matches: false,
+		},
+		{
+			name:    "other name",
+			probe:   &NameSelector{Interface: failingProbe{}, Name: "db-*"},
+			matches: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			success, message := test.probe.Probe(selectorTestObject())
+			if test.matches {
+				assert.False(t, success)
+				assert.Equal(t, "probed", message)
+				return
+			}
+			assert.True(t, success)
+			assert.Empty(t, message)
+		})
+	}
+}
+
+func TestNewNameSelector_invalid(t *testing.T) {
+	_, err := NewNameSelector(failingProbe{}, "", "web-[")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid pattern "web-["`)
+}